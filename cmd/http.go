@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 
 	"github.com/santhanuv/srotas/internal/http"
@@ -21,7 +22,8 @@ Send an HTTP request using the specified METHOD:
   - METHOD: The HTTP method to use (GET, POST, PUT, DELETE, etc.).
   - URL: The target URL for the request.
 
-Optional flags allow you to add query parameters, headers, and a request body.`,
+Optional flags allow you to add query parameters, headers, and a request body,
+form fields or files.`,
 		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := run(cmd, args, out); err != nil {
@@ -41,6 +43,15 @@ Optional flags allow you to add query parameters, headers, and a request body.`,
 	httpCommand.Flags().StringP("body", "B", "",
 		"Provide a request body. Only JSON is supported.")
 
+	httpCommand.Flags().StringArrayP("form", "F", []string{},
+		"Add a form field as 'key=value'. The fields are sent as a form-urlencoded body, or as a multipart body with --file.")
+
+	httpCommand.Flags().StringArray("file", []string{},
+		"Add a file as 'key=path', sent as a part of a multipart/form-data body.")
+
+	httpCommand.Flags().String("response-format", http.AutoFormat,
+		fmt.Sprintf("Format of the response body, one of %s. By default, it is detected from the Content-Type of the response.", strings.Join(http.Formats, ", ")))
+
 	return &httpCommand
 }
 
@@ -65,10 +76,6 @@ func run(cmd *cobra.Command, args []string, out io.Writer) error {
 
 	headers, err := parseHeaders(rawHeaders)
 
-	if _, ok := headers["Content-Type"]; !ok {
-		headers["Content-Type"] = []string{"application/json"}
-	}
-
 	if err != nil {
 		return fmt.Errorf("error on parsing header: %v", err)
 	}
@@ -78,31 +85,142 @@ func run(cmd *cobra.Command, args []string, out io.Writer) error {
 		return fmt.Errorf("error on parsing request body: %v", err)
 	}
 
+	requestBody, contentType, err := parseFormBody(cmd)
+	if err != nil {
+		return fmt.Errorf("error on parsing request body: %v", err)
+	}
+
+	switch {
+	case contentType != "" && rawRequestBody != "":
+		return fmt.Errorf("error on parsing request body: --body cannot be used with --form or --file")
+	case contentType != "":
+		for name := range headers {
+			if strings.EqualFold(name, "Content-Type") {
+				delete(headers, name)
+			}
+		}
+
+		headers["Content-Type"] = []string{contentType}
+	default:
+		requestBody = []byte(rawRequestBody)
+
+		if _, ok := headers["Content-Type"]; !ok {
+			headers["Content-Type"] = []string{"application/json"}
+		}
+	}
+
+	format, err := cmd.Flags().GetString("response-format")
+	if err != nil {
+		return fmt.Errorf("error on parsing response format: %v", err)
+	}
+
+	if !slices.Contains(http.Formats, format) {
+		return fmt.Errorf("response format should be one of %s", strings.Join(http.Formats, ", "))
+	}
+
 	req := &http.Request{
 		Method:      method,
 		Url:         rawURL,
 		Headers:     headers,
 		QueryParams: queryParams,
-		Body:        []byte(rawRequestBody),
+		Body:        requestBody,
 	}
 
 	c := http.NewClient(0)
-	res, err := c.Do(req)
+	res, err := c.Do(cmd.Context(), req)
 	if err != nil {
 		return fmt.Errorf("failed to execute http request: %v", err)
 	}
 
-	var responseJson bytes.Buffer
-	err = json.Indent(&responseJson, res.Body, "", " ")
+	output, err := formatResponse(res, format)
 	if err != nil {
 		return fmt.Errorf("failed to parse response: %s", err)
 	}
 
 	out.Write([]byte("Response:\n"))
-	out.Write([]byte(responseJson.String()))
+	out.Write(output)
 	return nil
 }
 
+// formatResponse returns the body of the response for display, decoded in the given format.
+// JSON and XML are displayed as indented JSON, and binary content as its size and hash.
+func formatResponse(res *http.Response, format string) ([]byte, error) {
+	if format == http.AutoFormat {
+		format = res.Format()
+	}
+
+	if len(res.Body) == 0 {
+		return []byte("(empty body)\n"), nil
+	}
+
+	if format == http.TextFormat {
+		return res.Body, nil
+	}
+
+	if format == http.JsonFormat {
+		var responseJson bytes.Buffer
+		if err := json.Indent(&responseJson, res.Body, "", " "); err != nil {
+			return nil, err
+		}
+
+		return responseJson.Bytes(), nil
+	}
+
+	body, err := res.Decode(format)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(body, "", " ")
+}
+
+// parseFormBody returns the form-urlencoded body of the form fields, or the multipart body if files are provided,
+// along with its content type. An empty content type is returned if no form fields or files are provided.
+func parseFormBody(cmd *cobra.Command) ([]byte, string, error) {
+	rawFields, err := cmd.Flags().GetStringArray("form")
+	if err != nil {
+		return nil, "", err
+	}
+
+	rawFiles, err := cmd.Flags().GetStringArray("file")
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(rawFields) == 0 && len(rawFiles) == 0 {
+		return nil, "", nil
+	}
+
+	fields := make(map[string][]string)
+
+	for _, rf := range rawFields {
+		key, value, ok := strings.Cut(rf, "=")
+		if !ok {
+			return nil, "", fmt.Errorf("Invalid form field: %s", rf)
+		}
+
+		fields[key] = append(fields[key], value)
+	}
+
+	if len(rawFiles) == 0 {
+		body, contentType := http.EncodeForm(fields)
+		return body, contentType, nil
+	}
+
+	files := make([]http.FilePart, 0, len(rawFiles))
+
+	for _, rf := range rawFiles {
+		key, path, ok := strings.Cut(rf, "=")
+		if !ok {
+			return nil, "", fmt.Errorf("Invalid file: %s", rf)
+		}
+
+		files = append(files, http.FilePart{Field: key, Path: path})
+	}
+
+	return http.EncodeMultipart(fields, files)
+}
+
 func parseQueryParams(rawQueryParams []string) (map[string][]string, error) {
 	queryParams := make(map[string][]string)
 
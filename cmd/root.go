@@ -29,3 +29,15 @@ func NewRootCmd(logger *log.Logger, in *os.File, out io.Writer) *cobra.Command {
 
 	return cmd
 }
+
+// Execute runs the srotas command line with the arguments of the process and exits with a non-zero status on failure.
+// Custom binaries register their step types with RegisterStep of the workflow package before calling Execute.
+func Execute() {
+	logger := log.New(os.Stderr, os.Stderr, os.Stderr)
+
+	rootCmd := NewRootCmd(logger, os.Stdin, os.Stdout)
+
+	if err := rootCmd.Execute(); err != nil {
+		logger.Fatal("%v", err)
+	}
+}
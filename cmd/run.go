@@ -63,6 +63,22 @@ func newRunCommand(logger *log.Logger, in *os.File, out io.Writer, cr *config.Co
 		The value supports expressions, allowing dynamic header generation using defined
 		or command-line variables.`)
 
+	runCommand.Flags().StringP("answers", "A", "", `
+		Reads the answers for prompt steps from a YAML or JSON file instead of the
+		terminal. The file maps the name of each prompt step to its answer, or to a
+		list of answers used in order when the step is executed more than once.
+		Required for prompt steps when stdin is not a terminal.`)
+
+	runCommand.Flags().Duration("timeout", 0, `
+		Limits the time allowed for the execution of the configuration, for example
+		30s or 5m. Once the limit is reached, the execution stops before the next
+		step and the teardown steps are executed. Zero means no limit.`)
+
+	runCommand.Flags().String("cookie-jar", "", `
+		Loads the cookie jar from the file before the execution and saves it to the
+		file afterwards, so that successive runs reuse the same session. Overrides
+		the cookies.file field of the configuration.`)
+
 	runCommand.Flags().StringArrayP("var", "V", nil, `
 		Defines a global variable in the format name=value, where the value is an expression.
 		Variables must be unique; redefining an existing one results in an error.`)
@@ -122,8 +138,33 @@ func parseCommand(cr *config.ConfigRunner, cmd *cobra.Command, args []string) er
 		return fmt.Errorf("invalid value for 'var': %v", err)
 	}
 
+	// Answers flag
+	answersPath, err := cmd.Flags().GetString("answers")
+	if err != nil {
+		return fmt.Errorf("invalid value for 'answers': %v", err)
+	}
+
+	// Timeout flag
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	if err != nil {
+		return fmt.Errorf("invalid value for 'timeout': %v", err)
+	}
+
+	if timeout < 0 {
+		return fmt.Errorf("invalid value for 'timeout': should not be negative")
+	}
+
+	// Cookie jar flag
+	cookieJarPath, err := cmd.Flags().GetString("cookie-jar")
+	if err != nil {
+		return fmt.Errorf("invalid value for 'cookie-jar': %v", err)
+	}
+
 	cr.CfgPath = configPath
 	cr.Debug = debugMode
+	cr.AnswersPath = answersPath
+	cr.Timeout = timeout
+	cr.CookieJarPath = cookieJarPath
 
 	if err := cr.AddVars(fVars); err != nil {
 		return err
@@ -6,6 +6,7 @@ require (
 	github.com/expr-lang/expr v1.16.9
 	github.com/spf13/cobra v1.8.1
 	github.com/tidwall/sjson v1.2.5
+	golang.org/x/term v0.27.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
@@ -15,4 +16,5 @@ require (
 	github.com/tidwall/gjson v1.18.0 // indirect
 	github.com/tidwall/match v1.1.1 // indirect
 	github.com/tidwall/pretty v1.2.1 // indirect
+	golang.org/x/sys v0.28.0 // indirect
 )
@@ -2,13 +2,18 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
+	"strings"
+	"time"
 
 	"github.com/expr-lang/expr"
 	"github.com/santhanuv/srotas/internal/http"
 	"github.com/santhanuv/srotas/internal/log"
+	"github.com/santhanuv/srotas/internal/prompt"
 	"github.com/santhanuv/srotas/internal/store"
 	"github.com/santhanuv/srotas/workflow"
 )
@@ -21,6 +26,9 @@ type ConfigRunner struct {
 	gHeaderExpr    map[string][]string // Global header expressions.
 	InputVars      map[string]any      // Compiled input variables.
 	DefHttpTimeout uint                // Default timeout (in ms) for HTTP request.
+	AnswersPath    string              // Path to the file with answers for prompt steps, used instead of the terminal.
+	Timeout        time.Duration       // Maximum time allowed for the execution of the configuration, zero means no limit.
+	CookieJarPath  string              // Path to the file the cookie jar is loaded from and saved to, overriding the configuration.
 }
 
 // Run runs the configuration.
@@ -70,11 +78,52 @@ func (cr ConfigRunner) Run(logger *log.Logger, in *os.File, out io.Writer) error
 		s.Add(variables)
 	}
 
-	httpClient := http.NewClient(cr.DefHttpTimeout)
+	httpTimeout := cr.DefHttpTimeout
+	if def.Timeout != 0 {
+		httpTimeout = def.Timeout
+	}
+
+	httpClient := http.NewClient(httpTimeout)
+
+	cookieOpts := def.Cookies
+	if cr.CookieJarPath != "" {
+		opts := workflow.CookieOptions{}
+		if cookieOpts != nil {
+			opts = *cookieOpts
+		}
+
+		opts.File = cr.CookieJarPath
+		cookieOpts = &opts
+	}
+
+	jar, err := cookieOpts.NewJar(def.BaseUrl, s.Map())
+	if err != nil {
+		return fmt.Errorf("failed to initialize cookie jar: %v", err)
+	}
+
+	httpClient.SetJar(jar)
+
+	// The terminal is restored in case the execution ends while a secret question is abandoned.
+	terminal := prompt.NewTerminal(in, os.Stderr)
+	defer terminal.Restore()
+
+	var prompter workflow.Prompter = terminal
+
+	if cr.AnswersPath != "" {
+		answers, err := prompt.LoadAnswers(cr.AnswersPath)
+		if err != nil {
+			return fmt.Errorf("failed to load answers: %v", err)
+		}
+
+		prompter = answers
+	}
 
 	execCtx, err := workflow.NewExecutionContext(
 		workflow.WithHttpClient(httpClient),
+		workflow.WithPrompter(prompter),
 		workflow.WithGlobalOptions(def.BaseUrl, headers),
+		workflow.WithMaxIterations(def.MaxIterations),
+		workflow.WithCookieJar(jar),
 		workflow.WithLogger(logger),
 		workflow.WithStore(s))
 
@@ -85,15 +134,48 @@ func (cr ConfigRunner) Run(logger *log.Logger, in *os.File, out io.Writer) error
 	// Execution
 	logger.Debug("executing configuration...")
 
-	err = workflow.Execute(def, execCtx)
-	if err != nil {
-		return fmt.Errorf("failed to execute config: %v", err)
+	stopInterrupt := interruptOnSignal(logger, execCtx, terminal.Restore)
+	stopTimeout := interruptOnTimeout(cr.Timeout, execCtx)
+	execErr := workflow.Execute(def, execCtx)
+	stopTimeout()
+	stopInterrupt()
+
+	// The cookie jar is saved even if the execution fails, so that a session established before the failure is reused.
+	if jar != nil && cookieOpts != nil && cookieOpts.File != "" {
+		if err := jar.Save(cookieOpts.File); err != nil {
+			logger.Error("failed to save cookie jar to '%s': %v", cookieOpts.File, err)
+		}
+	}
+
+	// An interrupted execution still emits the output of the variables set before the interruption.
+	var reason error
+
+	if execErr != nil {
+		reason = execCtx.Interrupted()
+
+		if reason == nil {
+			return fmt.Errorf("failed to execute config: %v", execErr)
+		}
+
+		var stepErr *workflow.StepError
+		if errors.As(execErr, &stepErr) {
+			logger.Error("execution interrupted at step '%s': %v", stepErr.Step, reason)
+		} else {
+			logger.Error("execution interrupted: %v", reason)
+		}
 	}
 
 	// Output updated variables
 	if def.OutputAll || def.Output != nil {
 		logger.Debug("output is being send to stdout")
-		outJson, err := compileOutput(def.Output, execCtx.Variables(), def.OutputAll)
+
+		var outJson []byte
+
+		if reason != nil {
+			outJson, err = compilePartialOutput(logger, def, execCtx.Variables())
+		} else {
+			outJson, err = compileOutput(def, execCtx.Variables())
+		}
 
 		if err != nil {
 			return fmt.Errorf("failed to encode output as json: %v", err)
@@ -112,11 +194,66 @@ func (cr ConfigRunner) Run(logger *log.Logger, in *os.File, out io.Writer) error
 		}
 	}
 
+	if reason != nil {
+		return fmt.Errorf("failed to execute config: %v", execErr)
+	}
+
 	logger.Debug("config executed successfully.")
 
 	return nil
 }
 
+// interruptOnSignal interrupts the execution of execCtx when an interrupt signal is received,
+// which allows the teardown steps to be executed before exiting.
+// Once interrupted, a subsequent interrupt signal terminates the program immediately after calling restore,
+// which restores the state of the terminal.
+// The returned function stops listening for the signal.
+func interruptOnSignal(logger *log.Logger, execCtx *workflow.ExecutionContext, restore func()) func() {
+	sigs := make(chan os.Signal, 1)
+	done := make(chan struct{})
+
+	signal.Notify(sigs, os.Interrupt)
+
+	go func() {
+		select {
+		case <-sigs:
+			logger.Error("interrupt received, stopping execution. Press Ctrl+C again to exit immediately.")
+			execCtx.Interrupt(workflow.ErrInterrupted)
+		case <-done:
+			return
+		}
+
+		select {
+		case <-sigs:
+			restore()
+			logger.Error("interrupt received again, exiting.")
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigs)
+		close(done)
+	}
+}
+
+// interruptOnTimeout interrupts the execution of execCtx once the timeout has elapsed.
+// A zero timeout means no limit. The returned function stops the timer.
+func interruptOnTimeout(timeout time.Duration, execCtx *workflow.ExecutionContext) func() {
+	if timeout == 0 {
+		return func() {}
+	}
+
+	timer := time.AfterFunc(timeout, func() {
+		execCtx.Interrupt(fmt.Errorf("run timeout of %s exceeded", timeout))
+	})
+
+	return func() {
+		timer.Stop()
+	}
+}
+
 // AddVars merges the given variables into the [ConfigRunner].
 // Each key-value pair represents a variable name and its corresponding expr expression.
 // Returns an error if a variable with the same name already exists.
@@ -203,28 +340,17 @@ func (cr ConfigRunner) Compile() (map[string]any, map[string][]string, error) {
 	return cVars, cHeaders, nil
 }
 
-// compileOutput evaluates expressions in out using vars as the environment and returns a JSON representation of the output.
-// If outputAll is true, all variables will be included in the output.
-func compileOutput(out map[string]string, vars map[string]any, outputAll bool) ([]byte, error) {
-	if out == nil && !outputAll {
+// compileOutput evaluates the output expressions of def using vars as the environment and returns a JSON representation of the output.
+// If output_all is set in def, all variables will be included in the output.
+func compileOutput(def *workflow.Definition, vars map[string]any) ([]byte, error) {
+	if def.Output == nil && !def.OutputAll {
 		return nil, fmt.Errorf("output error: please ensure output field exists")
 	}
 
-	var oVars map[string]any
-
-	if outputAll {
-		oVars = vars
-	} else {
-		oVars = make(map[string]any, len(out))
-		for vn, ve := range out {
-			val, err := expr.Eval(ve, vars)
+	oVars, err := def.EvalOutput(vars)
 
-			if err != nil {
-				return nil, err
-			}
-
-			oVars[vn] = val
-		}
+	if err != nil {
+		return nil, err
 	}
 
 	output := struct {
@@ -242,6 +368,24 @@ func compileOutput(out map[string]string, vars map[string]any, outputAll bool) (
 	return outJson, nil
 }
 
+// compilePartialOutput returns a JSON representation of the output of an interrupted execution.
+// Outputs that cannot be evaluated are omitted and logged.
+func compilePartialOutput(logger *log.Logger, def *workflow.Definition, vars map[string]any) ([]byte, error) {
+	oVars, omitted := def.EvalPartialOutput(vars)
+
+	if len(omitted) > 0 {
+		logger.Error("partial output: omitted %s, which could not be evaluated", strings.Join(omitted, ", "))
+	}
+
+	output := struct {
+		Variables map[string]any
+	}{
+		Variables: oVars,
+	}
+
+	return json.MarshalIndent(output, "", " ")
+}
+
 func NewConfigRunner() *ConfigRunner {
 	return &ConfigRunner{
 		sVarExpr:       map[string]string{},
@@ -1,6 +1,9 @@
 package http
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"time"
 )
@@ -8,41 +11,88 @@ import (
 // Client represents an http client
 // It uses the native client from net/http package
 type Client struct {
-	httpClient http.Client // the underlying http client
+	httpClient http.Client   // the underlying http client
+	timeout    time.Duration // the default timeout for requests, zero means no timeout
 }
 
-// NewClient returns a new http client with the specified timeout in milliseconds
-func NewClient(timeout uint) *Client {
-	timeoutMS := time.Duration.Milliseconds(time.Duration(timeout))
+// TimeoutError is returned when a request does not complete within its timeout.
+type TimeoutError struct {
+	Timeout time.Duration // the timeout that was exceeded
+}
 
-	c := http.Client{
-		Timeout: time.Duration(timeoutMS),
-	}
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("request timed out after %s", e.Timeout)
+}
 
+// NewClient returns a new http client with the specified timeout in milliseconds.
+// A zero timeout means no timeout.
+func NewClient(timeout uint) *Client {
 	return &Client{
-		httpClient: c,
+		timeout: time.Duration(timeout) * time.Millisecond,
+	}
+}
+
+// SetJar sets the cookie jar used to store the cookies of responses and send them with requests.
+// A nil jar disables cookies.
+func (hc *Client) SetJar(jar *Jar) {
+	if jar == nil {
+		hc.httpClient.Jar = nil
+		return
 	}
+
+	hc.httpClient.Jar = jar
 }
 
 // Do sends an http request and returns an http resposne
-func (hc *Client) Do(request *Request) (*Response, error) {
-	req, err := request.buildNative()
+// The timeout of the request takes precedence over the timeout of the client.
+// If ctx is cancelled, the request is aborted and the cause of the cancellation is returned.
+func (hc *Client) Do(ctx context.Context, request *Request) (*Response, error) {
+	req, err := request.buildNative(ctx)
 
 	if err != nil {
 		return nil, err
 	}
 
+	timeout := hc.timeout
+	if request.Timeout != 0 {
+		timeout = time.Duration(request.Timeout) * time.Millisecond
+	}
+
+	if timeout != 0 {
+		tCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		req = req.WithContext(tCtx)
+	}
+
+	start := time.Now()
 	res, err := hc.httpClient.Do(req)
 
 	if err != nil {
-		return nil, err
+		return nil, doError(ctx, timeout, err)
 	}
 
 	response, err := buildFromNative(res)
 
 	if err != nil {
-		return nil, err
+		return nil, doError(ctx, timeout, err)
 	}
 
+	response.Elapsed = time.Since(start)
+
 	return response, nil
 }
+
+// doError returns the error for a request that failed with err.
+// If the request is aborted because ctx is cancelled, the cause is returned; if the timeout is exceeded, a [TimeoutError] is returned.
+func doError(ctx context.Context, timeout time.Duration, err error) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("request aborted: %w", context.Cause(ctx))
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &TimeoutError{Timeout: timeout}
+	}
+
+	return err
+}
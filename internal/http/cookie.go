@@ -0,0 +1,258 @@
+package http
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Jar is a cookie jar that keeps the cookies set by responses and sends them with the following requests.
+// Unlike the jar of net/http/cookiejar, its cookies can be listed, saved to a file and loaded from it.
+type Jar struct {
+	mu      sync.Mutex
+	jar     *cookiejar.Jar      // the underlying jar that matches cookies to requests
+	entries map[string]jarEntry // cookies set in the jar by domain, path and name
+	seq     uint64              // incremented for each cookie set in the jar
+}
+
+// jarEntry represents a cookie set in the [Jar] with the URL of the response that set it.
+type jarEntry struct {
+	Url    string       // URL of the response that set the cookie.
+	Cookie *http.Cookie // The cookie, with its expiry as a time.
+	seq    uint64       // Order in which the cookie was set.
+}
+
+// savedCookie represents a cookie in the file written by [Jar.Save].
+type savedCookie struct {
+	Url      string     `json:"url"`
+	Name     string     `json:"name"`
+	Value    string     `json:"value"`
+	Domain   string     `json:"domain,omitempty"`
+	Path     string     `json:"path,omitempty"`
+	Expires  *time.Time `json:"expires,omitempty"`
+	Secure   bool       `json:"secure,omitempty"`
+	HttpOnly bool       `json:"http_only,omitempty"`
+}
+
+// NewJar returns an empty cookie jar.
+func NewJar() *Jar {
+	jar, _ := cookiejar.New(nil)
+
+	return &Jar{
+		jar:     jar,
+		entries: map[string]jarEntry{},
+	}
+}
+
+// SetCookies stores the cookies set by a response from u.
+func (j *Jar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.jar.SetCookies(u, cookies)
+
+	for _, cookie := range cookies {
+		domain := cookie.Domain
+		if domain == "" {
+			domain = u.Hostname()
+		}
+
+		key := fmt.Sprintf("%s;%s;%s", domain, cookie.Path, cookie.Name)
+
+		if cookie.MaxAge < 0 || (!cookie.Expires.IsZero() && cookie.Expires.Before(time.Now())) {
+			delete(j.entries, key)
+			continue
+		}
+
+		// The expiry is kept as a time so that it is not extended when the jar is saved and loaded again.
+		stored := *cookie
+		if stored.MaxAge > 0 {
+			stored.Expires = time.Now().Add(time.Duration(stored.MaxAge) * time.Second)
+			stored.MaxAge = 0
+		}
+
+		j.seq++
+		j.entries[key] = jarEntry{Url: u.String(), Cookie: &stored, seq: j.seq}
+	}
+}
+
+// Cookies returns the cookies to send in a request to u.
+func (j *Jar) Cookies(u *url.URL) []*http.Cookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.jar.Cookies(u)
+}
+
+// Get returns the value of the most recently set cookie with the given name, for any URL.
+// An empty string is returned if there is no such cookie or if it has expired.
+func (j *Jar) Get(name string) string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var latest *jarEntry
+
+	for _, entry := range j.live() {
+		if entry.Cookie.Name == name && (latest == nil || entry.seq > latest.seq) {
+			latest = &entry
+		}
+	}
+
+	if latest == nil {
+		return ""
+	}
+
+	return latest.Cookie.Value
+}
+
+// Values returns the values of the cookies in the jar by name.
+// If cookies with the same name are set for different URLs, the most recently set one is used.
+func (j *Jar) Values() map[string]string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	values := map[string]string{}
+	seqs := map[string]uint64{}
+
+	for _, entry := range j.live() {
+		if name := entry.Cookie.Name; entry.seq > seqs[name] {
+			values[name] = entry.Cookie.Value
+			seqs[name] = entry.seq
+		}
+	}
+
+	return values
+}
+
+// For returns the values of the cookies sent in a request to rawURL by name.
+func (j *Jar) For(rawURL string) (map[string]string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]string{}
+
+	for _, cookie := range j.Cookies(u) {
+		values[cookie.Name] = cookie.Value
+	}
+
+	return values, nil
+}
+
+// MarshalJSON encodes the values of the cookies in the jar by name.
+func (j *Jar) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.Values())
+}
+
+// live returns the entries of the cookies that have not expired.
+// It must be called with j.mu held.
+func (j *Jar) live() []jarEntry {
+	now := time.Now()
+	entries := make([]jarEntry, 0, len(j.entries))
+
+	for _, entry := range j.entries {
+		if !entry.Cookie.Expires.IsZero() && entry.Cookie.Expires.Before(now) {
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// Load adds the cookies saved by [Jar.Save] at path to the jar.
+// A missing file is not an error, so that the first run starts with an empty jar.
+func (j *Jar) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var cookies []savedCookie
+	if err := json.Unmarshal(data, &cookies); err != nil {
+		return fmt.Errorf("invalid cookie jar '%s': %v", path, err)
+	}
+
+	for _, c := range cookies {
+		u, err := url.Parse(c.Url)
+		if err != nil {
+			return fmt.Errorf("invalid cookie jar '%s': %v", path, err)
+		}
+
+		cookie := http.Cookie{
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			Secure:   c.Secure,
+			HttpOnly: c.HttpOnly,
+		}
+
+		if c.Expires != nil {
+			cookie.Expires = *c.Expires
+		}
+
+		j.SetCookies(u, []*http.Cookie{&cookie})
+	}
+
+	return nil
+}
+
+// Save writes the cookies in the jar that have not expired to the file at path, creating missing parent directories.
+// Session cookies, which have no expiry, are saved as well so that the following runs reuse the same session.
+func (j *Jar) Save(path string) error {
+	j.mu.Lock()
+	entries := j.live()
+	j.mu.Unlock()
+
+	slices.SortFunc(entries, func(a, b jarEntry) int {
+		return cmp.Compare(a.seq, b.seq)
+	})
+
+	cookies := make([]savedCookie, 0, len(entries))
+
+	for _, entry := range entries {
+		c := savedCookie{
+			Url:      entry.Url,
+			Name:     entry.Cookie.Name,
+			Value:    entry.Cookie.Value,
+			Domain:   entry.Cookie.Domain,
+			Path:     entry.Cookie.Path,
+			Secure:   entry.Cookie.Secure,
+			HttpOnly: entry.Cookie.HttpOnly,
+		}
+
+		if !entry.Cookie.Expires.IsZero() {
+			c.Expires = &entry.Cookie.Expires
+		}
+
+		cookies = append(cookies, c)
+	}
+
+	data, err := json.MarshalIndent(cookies, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
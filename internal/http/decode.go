@@ -0,0 +1,197 @@
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"unicode/utf8"
+)
+
+// Formats of the response body decoded by [Response.Decode].
+const (
+	AutoFormat   = "auto"   // Format detected from the Content-Type of the response.
+	JsonFormat   = "json"   // JSON document.
+	XmlFormat    = "xml"    // XML document, decoded into a map.
+	TextFormat   = "text"   // Text, decoded into a string.
+	BinaryFormat = "binary" // Binary content, decoded into its base64 encoded content, size and hash.
+)
+
+// Formats lists the formats supported by [Response.Decode].
+var Formats = []string{AutoFormat, JsonFormat, XmlFormat, TextFormat, BinaryFormat}
+
+// Header returns the first value of the response header name, matched case-insensitively.
+func (r *Response) Header(name string) string {
+	for key, values := range r.Headers {
+		if strings.EqualFold(key, name) && len(values) > 0 {
+			return values[0]
+		}
+	}
+
+	return ""
+}
+
+// Format returns the format of the response body based on its Content-Type.
+// If the Content-Type is missing or invalid, the body is detected as JSON, text or binary.
+func (r *Response) Format() string {
+	mediaType, _, err := mime.ParseMediaType(r.Header("Content-Type"))
+	if err != nil {
+		switch {
+		case json.Valid(r.Body):
+			return JsonFormat
+		case utf8.Valid(r.Body):
+			return TextFormat
+		}
+
+		return BinaryFormat
+	}
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return JsonFormat
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return XmlFormat
+	case strings.HasPrefix(mediaType, "text/"):
+		return TextFormat
+	}
+
+	switch mediaType {
+	case "application/javascript", "application/x-www-form-urlencoded", "application/yaml", "application/x-yaml", "application/graphql":
+		return TextFormat
+	}
+
+	return BinaryFormat
+}
+
+// Decode decodes the response body in the given format, or in the format of the response for [AutoFormat] or an empty format.
+//   - JSON is decoded into the corresponding values.
+//   - XML is decoded into a map from the name of the root element to its value. An element without attributes
+//     and child elements is decoded into its text, and any other element into a map of its child elements,
+//     its attributes prefixed with '@' and its text as '#text'. Repeated child elements are decoded into a list.
+//   - Text is decoded into a string.
+//   - Binary content is decoded into a map with its base64 encoded content, size, sha256 hash and content type.
+//
+// An empty body is decoded into nil.
+func (r *Response) Decode(format string) (any, error) {
+	if len(r.Body) == 0 {
+		return nil, nil
+	}
+
+	if format == "" || format == AutoFormat {
+		format = r.Format()
+	}
+
+	switch format {
+	case JsonFormat:
+		var body any
+		if err := json.Unmarshal(r.Body, &body); err != nil {
+			return nil, fmt.Errorf("invalid json: %v", err)
+		}
+
+		return body, nil
+	case XmlFormat:
+		body, err := decodeXml(r.Body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid xml: %v", err)
+		}
+
+		return body, nil
+	case TextFormat:
+		return string(r.Body), nil
+	case BinaryFormat:
+		hash := sha256.Sum256(r.Body)
+
+		return map[string]any{
+			"content":      base64.StdEncoding.EncodeToString(r.Body),
+			"size":         len(r.Body),
+			"sha256":       hex.EncodeToString(hash[:]),
+			"content_type": r.Header("Content-Type"),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported response format '%s'", format)
+}
+
+// decodeXml decodes the XML document in data into a map from the name of the root element to its value.
+func decodeXml(data []byte) (any, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return nil, fmt.Errorf("no root element")
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if start, ok := tok.(xml.StartElement); ok {
+			val, err := decodeXmlElement(d, start)
+			if err != nil {
+				return nil, err
+			}
+
+			return map[string]any{start.Name.Local: val}, nil
+		}
+	}
+}
+
+// decodeXmlElement decodes the element started by start, reading the tokens up to its end.
+func decodeXmlElement(d *xml.Decoder, start xml.StartElement) (any, error) {
+	element := map[string]any{}
+
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+			continue
+		}
+
+		element["@"+attr.Name.Local] = attr.Value
+	}
+
+	var text strings.Builder
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			child, err := decodeXmlElement(d, t)
+			if err != nil {
+				return nil, err
+			}
+
+			switch existing := element[t.Name.Local].(type) {
+			case nil:
+				element[t.Name.Local] = child
+			case []any:
+				element[t.Name.Local] = append(existing, child)
+			default:
+				element[t.Name.Local] = []any{existing, child}
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			content := strings.TrimSpace(text.String())
+
+			if len(element) == 0 {
+				return content, nil
+			}
+
+			if content != "" {
+				element["#text"] = content
+			}
+
+			return element, nil
+		}
+	}
+}
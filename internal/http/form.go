@@ -0,0 +1,94 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// FilePart represents a file sent as a part of a multipart/form-data body.
+type FilePart struct {
+	Field string // Name of the form field.
+	Path  string // Path of the file to send.
+}
+
+// EncodeForm encodes the fields as an application/x-www-form-urlencoded body.
+// It returns the body along with its content type.
+func EncodeForm(fields map[string][]string) ([]byte, string) {
+	return []byte(url.Values(fields).Encode()), "application/x-www-form-urlencoded"
+}
+
+// EncodeMultipart encodes the fields and files as a multipart/form-data body, with the fields sorted by name
+// followed by the files. The content type of each file is detected from its extension.
+// It returns the body along with its content type, which includes the boundary of the parts.
+func EncodeMultipart(fields map[string][]string, files []FilePart) ([]byte, string, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		for _, value := range fields[name] {
+			if err := w.WriteField(name, value); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+
+	for _, file := range files {
+		if err := writeFilePart(w, file); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return buf.Bytes(), w.FormDataContentType(), nil
+}
+
+// writeFilePart writes the file as a part of the multipart body.
+func writeFilePart(w *multipart.Writer, file FilePart) error {
+	f, err := os.Open(file.Path)
+	if err != nil {
+		return fmt.Errorf("file for field '%s': %v", file.Field, err)
+	}
+
+	defer f.Close()
+
+	contentType := mime.TypeByExtension(filepath.Ext(file.Path))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	escape := strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, escape.Replace(file.Field), escape.Replace(filepath.Base(file.Path))))
+	header.Set("Content-Type", contentType)
+
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(part, f); err != nil {
+		return fmt.Errorf("file for field '%s': %v", file.Field, err)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"net/http"
 	"net/url"
@@ -21,17 +22,20 @@ type Request struct {
 	Headers map[string][]string
 	// QueryParams specifies the query parameters to be added in the request URL
 	QueryParams map[string][]string
+	// Timeout specifies the maximum time (in ms) allowed for the request.
+	// A zero timeout means the timeout of the client is used.
+	Timeout uint
 }
 
-// buildNative builds the native http.Request from the custom Request type.
-func (hr *Request) buildNative() (*http.Request, error) {
+// buildNative builds the native http.Request from the custom Request type, bound to ctx.
+func (hr *Request) buildNative(ctx context.Context) (*http.Request, error) {
 	var body io.Reader
 
 	if hr.Body != nil {
 		body = bytes.NewBuffer(hr.Body)
 	}
 
-	req, err := http.NewRequest(hr.Method, hr.Url, body)
+	req, err := http.NewRequestWithContext(ctx, hr.Method, hr.Url, body)
 
 	if err != nil {
 		return nil, err
@@ -3,6 +3,7 @@ package http
 import (
 	"io"
 	"net/http"
+	"time"
 )
 
 // Response represents the response from an http request
@@ -11,6 +12,9 @@ type Response struct {
 	StatusCode uint                // specifies the http status code of response
 	Headers    map[string][]string // headers set in the response
 	Body       []byte              // the response body
+	Url        string              // the URL of the response, after following redirects
+	Cookies    []*http.Cookie      // cookies set by the response
+	Elapsed    time.Duration       // time taken to send the request and read the response
 }
 
 // buildFromNative creates a Response instance from the native http.Response instance
@@ -28,5 +32,7 @@ func buildFromNative(response *http.Response) (*Response, error) {
 		StatusCode: uint(response.StatusCode),
 		Headers:    response.Header,
 		Body:       responseBody,
+		Url:        response.Request.URL.String(),
+		Cookies:    response.Cookies(),
 	}, nil
 }
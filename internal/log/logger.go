@@ -40,6 +40,16 @@ func (l *Logger) SetConfig(fileName string) {
 	l.configName = fileName
 }
 
+// WithTag returns a copy of the logger that prefixes every line with the given tag.
+// The returned logger shares the output destinations and debug mode of l, which
+// makes it suitable for distinguishing logs of concurrently executing steps.
+func (l *Logger) WithTag(tag string) *Logger {
+	tl := *l
+	tl.configName = fmt.Sprintf("%s [%s]", l.configName, tag)
+
+	return &tl
+}
+
 // SetDebugOutput set the output destination for debug logs.
 func (l *Logger) SetDebugOutput(debug io.Writer) {
 	l.debug.SetOutput(debug)
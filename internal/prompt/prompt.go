@@ -0,0 +1,253 @@
+package prompt
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrNotTerminal is returned by [Terminal] when its input is not connected to a terminal.
+var ErrNotTerminal = errors.New("input is not a terminal, an answers file is required")
+
+// Question represents a question asked to the user.
+type Question struct {
+	Key      string             // Identifier of the question, used to look up answers in an answers file.
+	Message  string             // Message displayed to the user.
+	Secret   bool               // The answer is not echoed if true.
+	Validate func(string) error // Checks the answer; an empty answer means that no answer is provided.
+}
+
+// Terminal reads answers from a terminal.
+// If an answer is not valid, the error is displayed and the question is asked again.
+// A question that is abandoned because its context is done leaves its read pending,
+// and the line read is the answer to the next question. The input is then echoed as for the abandoned question,
+// except that it is echoed again if the abandoned question is secret and the next one is not.
+type Terminal struct {
+	turn    chan struct{}   // holds a token while a question is asked, so that only one question is asked at a time.
+	in      *os.File        // Input connected to the terminal.
+	out     io.Writer       // Writer to which questions are displayed.
+	reader  *bufio.Reader   // Buffered reader of in.
+	pending chan readResult // Result of the read in progress, nil if there is none. Guarded by turn.
+	secret  bool            // Whether the read in progress is not echoed. Guarded by turn.
+	mu      sync.Mutex      // guards state.
+	state   *term.State     // State of the terminal before the secret answer being read, nil if none is being read.
+}
+
+// readResult is the result of reading a line from the terminal.
+type readResult struct {
+	line string
+	err  error
+}
+
+// NewTerminal returns a new [Terminal] that reads answers from in and displays the questions to out.
+func NewTerminal(in *os.File, out io.Writer) *Terminal {
+	t := &Terminal{
+		turn:   make(chan struct{}, 1),
+		in:     in,
+		out:    out,
+		reader: bufio.NewReader(in),
+	}
+
+	t.turn <- struct{}{}
+
+	return t
+}
+
+// Ask displays the question and returns the answer read from the terminal.
+// If ctx is done before the question is answered, the cause of ctx is returned.
+func (t *Terminal) Ask(ctx context.Context, q Question) (string, error) {
+	select {
+	case <-t.turn:
+	case <-ctx.Done():
+		return "", context.Cause(ctx)
+	}
+
+	defer func() { t.turn <- struct{}{} }()
+
+	if !term.IsTerminal(int(t.in.Fd())) {
+		return "", ErrNotTerminal
+	}
+
+	for {
+		fmt.Fprintf(t.out, "%s ", q.Message)
+
+		answer, err := t.read(ctx, q.Secret)
+		if err != nil {
+			return "", err
+		}
+
+		if q.Validate == nil {
+			return answer, nil
+		}
+
+		if err := q.Validate(answer); err != nil {
+			fmt.Fprintf(t.out, "invalid answer: %v\n", err)
+			continue
+		}
+
+		return answer, nil
+	}
+}
+
+// Restore restores the state of the terminal if a secret answer is being read, so that the input is echoed again.
+// It is safe to call at any time, and should be called before exiting in case a secret question was abandoned.
+func (t *Terminal) Restore() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.state != nil {
+		term.Restore(int(t.in.Fd()), t.state)
+	}
+}
+
+// read reads a line from the terminal. If secret is true, the line is not echoed.
+// The read is done in a separate goroutine, so that it can be abandoned when ctx is done;
+// the pending read is then used by the next question. It must be called with the turn held.
+func (t *Terminal) read(ctx context.Context, secret bool) (string, error) {
+	switch {
+	case t.pending != nil && t.secret && !secret:
+		t.Restore()
+	case t.pending != nil && !t.secret && secret:
+		// The echo of the pending read cannot be turned off, so the user is warned instead.
+		fmt.Fprint(t.out, "(input is visible) ")
+	case t.pending == nil:
+		t.pending = make(chan readResult, 1)
+		t.secret = secret
+
+		if secret {
+			if state, err := term.GetState(int(t.in.Fd())); err == nil {
+				t.mu.Lock()
+				t.state = state
+				t.mu.Unlock()
+			}
+		}
+
+		go func(pending chan<- readResult) {
+			line, err := t.readLine(secret)
+			pending <- readResult{line: line, err: err}
+		}(t.pending)
+	}
+
+	select {
+	case res := <-t.pending:
+		t.pending = nil
+		return res.line, res.err
+	case <-ctx.Done():
+		fmt.Fprintln(t.out)
+
+		return "", context.Cause(ctx)
+	}
+}
+
+// readLine reads a line from the terminal, without echoing it if secret is true.
+func (t *Terminal) readLine(secret bool) (string, error) {
+	if secret {
+		line, err := term.ReadPassword(int(t.in.Fd()))
+
+		t.mu.Lock()
+		t.state = nil
+		t.mu.Unlock()
+
+		fmt.Fprintln(t.out)
+
+		return string(line), err
+	}
+
+	line, err := t.reader.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// Answers reads answers from a predefined set, which keeps prompts scriptable when no terminal is available.
+// Each question is answered by the entry with the same key. If the entry is a list, each time the question
+// is asked the next answer in the list is returned. A question without an answer receives an empty answer.
+type Answers struct {
+	mu      sync.Mutex          // guards asked.
+	answers map[string][]string // Answers mapped to the key of the question.
+	asked   map[string]int      // Number of times each question is asked.
+}
+
+// NewAnswers returns a new [Answers] with the given answers mapped to the key of the question.
+func NewAnswers(answers map[string][]string) *Answers {
+	return &Answers{
+		answers: answers,
+		asked:   map[string]int{},
+	}
+}
+
+// LoadAnswers reads the answers from a YAML or JSON file at path.
+// The file should contain a map of question keys to an answer or a list of answers.
+func LoadAnswers(path string) (*Answers, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid answers file '%s': %v", path, err)
+	}
+
+	answers := make(map[string][]string, len(raw))
+
+	for key, val := range raw {
+		switch v := val.(type) {
+		case nil:
+			answers[key] = []string{""}
+		case []any:
+			for _, item := range v {
+				answers[key] = append(answers[key], fmt.Sprint(item))
+			}
+		case map[string]any:
+			return nil, fmt.Errorf("invalid answers file '%s': answer for '%s' should be a value or a list", path, key)
+		default:
+			answers[key] = []string{fmt.Sprint(v)}
+		}
+	}
+
+	return NewAnswers(answers), nil
+}
+
+// Ask returns the next answer for the question. An invalid answer results in an error.
+// The answers are read without blocking, so ctx is only checked before the question is answered.
+func (a *Answers) Ask(ctx context.Context, q Question) (string, error) {
+	if ctx.Err() != nil {
+		return "", context.Cause(ctx)
+	}
+
+	a.mu.Lock()
+
+	var answer string
+
+	list := a.answers[q.Key]
+	asked := a.asked[q.Key]
+
+	switch {
+	case len(list) == 1:
+		answer = list[0]
+	case asked < len(list):
+		answer = list[asked]
+	}
+
+	a.asked[q.Key] = asked + 1
+	a.mu.Unlock()
+
+	if q.Validate != nil {
+		if err := q.Validate(answer); err != nil {
+			return "", fmt.Errorf("invalid answer for '%s': %w", q.Key, err)
+		}
+	}
+
+	return answer, nil
+}
@@ -2,11 +2,15 @@ package store
 
 import (
 	"maps"
+	"reflect"
+	"sync"
 )
 
 // Store provides a dynamic storage mechanism for variables during config execution.
 // It allows setting and retrieving variables as needed throughout execution.
+// A Store is safe for concurrent use by multiple goroutines.
 type Store struct {
+	mu        sync.RWMutex   // guards variables.
 	variables map[string]any // variables contained in the Store.
 }
 
@@ -24,28 +28,133 @@ func NewStore(ivars map[string]any) *Store {
 // Add merges the given variables into the Store.
 // If a variable with the same name already exists, it will be overwritten.
 func (s *Store) Add(vars map[string]any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	maps.Copy(s.variables, vars)
 }
 
 // Set sets the variable in the Store with given name and value.
 // If a variable with the same name already exists, it will be overwritten.
 func (s *Store) Set(name string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	s.variables[name] = value
 }
 
 // Get retrieves the value of the variable identified by the given name,
 // along with a boolean indicating whether the variable exists.
 func (s *Store) Get(name string) (any, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	val, ok := s.variables[name]
 	return val, ok
 }
 
 // Remove removes the variable identified by the given name.
 func (s *Store) Remove(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	delete(s.variables, name)
 }
 
-// Map returns a map of all variables, where the keys are variable names and the values are their respective values.
+// Map returns a snapshot of all variables, where the keys are variable names and the values are their respective values.
+// Modifying the returned map does not affect the Store.
 func (s *Store) Map() map[string]any {
-	return s.variables
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return maps.Clone(s.variables)
+}
+
+// Clone returns a new [Store] holding a deep copy of the variables in s.
+// Variables set, removed or modified in the clone, including the items of maps and lists, are not visible in s and vice versa.
+func (s *Store) Clone() *Store {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	variables := make(map[string]any, len(s.variables))
+	for name, val := range s.variables {
+		variables[name] = deepCopy(val)
+	}
+
+	return &Store{
+		variables: variables,
+	}
+}
+
+// deepCopy returns a copy of val where maps and slices are copied recursively.
+// Other values, including pointers, are shared with val.
+func deepCopy(val any) any {
+	switch v := val.(type) {
+	case map[string]any:
+		if v == nil {
+			return v
+		}
+
+		c := make(map[string]any, len(v))
+		for key, item := range v {
+			c[key] = deepCopy(item)
+		}
+
+		return c
+	case []any:
+		if v == nil {
+			return v
+		}
+
+		c := make([]any, len(v))
+		for idx, item := range v {
+			c[idx] = deepCopy(item)
+		}
+
+		return c
+	}
+
+	rv := reflect.ValueOf(val)
+
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.IsNil() {
+			return val
+		}
+
+		c := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), copyValue(iter.Value(), rv.Type().Elem()))
+		}
+
+		return c.Interface()
+	case reflect.Slice:
+		if rv.IsNil() {
+			return val
+		}
+
+		c := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		for idx := range rv.Len() {
+			c.Index(idx).Set(copyValue(rv.Index(idx), rv.Type().Elem()))
+		}
+
+		return c.Interface()
+	}
+
+	return val
+}
+
+// copyValue returns a deep copy of v as a value assignable to the type t.
+func copyValue(v reflect.Value, t reflect.Type) reflect.Value {
+	if !v.IsValid() || (v.Kind() == reflect.Interface && v.IsNil()) {
+		return reflect.Zero(t)
+	}
+
+	c := reflect.ValueOf(deepCopy(v.Interface()))
+	if !c.IsValid() {
+		return reflect.Zero(t)
+	}
+
+	return c
 }
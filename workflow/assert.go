@@ -0,0 +1,99 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AssertStep represents a step that checks assertions against the variables in the store without sending a request.
+type AssertStep struct {
+	Type     string      // The type of the step.
+	StepName string      `yaml:"name"` // Identifier for the step.
+	Asserts  []Assertion // Assertions evaluated in order.
+}
+
+// Assertion represents an [Assert] along with an optional message reported when it fails.
+// In YAML, it is either an expression or a map with the condition and message fields.
+type Assertion struct {
+	Condition Assert // Expression that must evaluate to true.
+	Message   string // Message reported if the assertion fails.
+}
+
+func (a *Assertion) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		return value.Decode(&a.Condition)
+	}
+
+	var rawAssertion struct {
+		Condition Assert
+		Message   string
+	}
+
+	if err := value.Decode(&rawAssertion); err != nil {
+		return err
+	}
+
+	*a = Assertion(rawAssertion)
+
+	return nil
+}
+
+// Validate checks the fields of the [AssertStep] and returns a list of validation errors, if any.
+func (a *AssertStep) Validate() error {
+	vErr := ValidationError{}
+
+	if a.StepName == "" {
+		vErr.Add(RequiredFieldError{Field: "name"})
+	}
+
+	if len(a.Asserts) == 0 {
+		vErr.Add(RequiredFieldError{Field: "asserts"})
+	}
+
+	for idx, assertion := range a.Asserts {
+		if assertion.Condition == "" {
+			vErr.Add(RequiredFieldError{Field: fmt.Sprintf("asserts[%d].condition", idx)})
+		}
+	}
+
+	if vErr.HasError() {
+		return fmt.Errorf("assert step: %w", &vErr)
+	}
+
+	return nil
+}
+
+func (a *AssertStep) Name() string {
+	return a.StepName
+}
+
+// Execute executes the step with the specified context.
+// All assertions are evaluated, and every failure is reported in the returned error.
+func (a *AssertStep) Execute(context *ExecutionContext) error {
+	vars := context.variables()
+
+	var failures []string
+
+	for _, assertion := range a.Asserts {
+		err := assertion.Condition.Validate(vars, nil)
+		if err == nil {
+			continue
+		}
+
+		if assertion.Message != "" {
+			err = fmt.Errorf("%s: %w", assertion.Message, err)
+		}
+
+		failures = append(failures, err.Error())
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("assert step '%s' failed:\n\t%s", a.StepName, strings.Join(failures, "\n\t"))
+	}
+
+	context.logger.Debug("all assertions of assert step '%s' passed", a.StepName)
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package workflow
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/santhanuv/srotas/internal/log"
+	"github.com/santhanuv/srotas/internal/store"
+	"gopkg.in/yaml.v3"
+)
+
+func TestAssertStep_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		assert AssertStep
+		err    bool
+	}{
+		{
+			name: "'name' is not provided",
+			assert: AssertStep{
+				Type:    "assert",
+				Asserts: []Assertion{{Condition: "true"}},
+			},
+			err: true,
+		},
+		{
+			name: "'asserts' field is not provided",
+			assert: AssertStep{
+				Type:     "assert",
+				StepName: "name",
+			},
+			err: true,
+		},
+		{
+			name: "assertion condition is not provided",
+			assert: AssertStep{
+				Type:     "assert",
+				StepName: "name",
+				Asserts:  []Assertion{{Message: "message"}},
+			},
+			err: true,
+		},
+		{
+			name: "All required fields are provided",
+			assert: AssertStep{
+				Type:     "assert",
+				StepName: "name",
+				Asserts:  []Assertion{{Condition: "true"}},
+			},
+			err: false,
+		},
+	}
+
+	for _, tt := range tests {
+		err := tt.assert.Validate()
+
+		if !tt.err && err != nil {
+			t.Errorf("in test %q; expected no error but got %q", tt.name, err)
+			continue
+		}
+
+		if tt.err {
+			if err == nil {
+				t.Errorf("in test %q; expected error but got none", tt.name)
+				continue
+			}
+
+			var target *ValidationError
+			if !errors.As(err, &target) {
+				t.Errorf("in test %q; got %q; want validation error", tt.name, err)
+			}
+		}
+	}
+}
+
+func TestAssertStep_Execute(t *testing.T) {
+	tests := []struct {
+		name     string
+		step     string
+		failures []string
+	}{
+		{
+			name: "all assertions pass",
+			step: `
+name: totals
+asserts:
+  - "len(orders) == 2"
+  - condition: "sum(map(orders, .total)) == total"
+    message: "order totals do not match"
+`,
+		},
+		{
+			name: "failures are reported with messages",
+			step: `
+name: totals
+asserts:
+  - "len(orders) == 3"
+  - condition: "total == 0"
+    message: "total should be zero"
+  - "total"
+`,
+			failures: []string{
+				"assertion 'len(orders) == 3' failed",
+				"total should be zero: assertion 'total == 0' failed",
+				"should produce a boolean",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		var step AssertStep
+		if err := yaml.Unmarshal([]byte(tt.step), &step); err != nil {
+			t.Fatalf("in test %q; failed to setup test: %v", tt.name, err)
+		}
+
+		s := store.NewStore(map[string]any{
+			"orders": []any{map[string]any{"total": 10}, map[string]any{"total": 5}},
+			"total":  15,
+		})
+		logBuf := bytes.NewBuffer(nil)
+
+		context, err := NewExecutionContext(WithStore(s), WithLogger(log.New(logBuf, logBuf, logBuf)))
+		if err != nil {
+			t.Fatalf("failed to setup test: unable to create execution context")
+		}
+
+		err = step.Execute(context)
+
+		if len(tt.failures) == 0 {
+			if err != nil {
+				t.Errorf("in test %q; expected no error but got %q", tt.name, err)
+			}
+			continue
+		}
+
+		if err == nil {
+			t.Errorf("in test %q; expected error but got none", tt.name)
+			continue
+		}
+
+		for _, failure := range tt.failures {
+			if !strings.Contains(err.Error(), failure) {
+				t.Errorf("in test %q; expected error to contain %q but got %q", tt.name, failure, err)
+			}
+		}
+	}
+}
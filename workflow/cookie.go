@@ -0,0 +1,106 @@
+package workflow
+
+import (
+	"fmt"
+	nethttp "net/http"
+	"net/url"
+	"path/filepath"
+
+	"github.com/expr-lang/expr"
+	"github.com/santhanuv/srotas/internal/http"
+	"gopkg.in/yaml.v3"
+)
+
+// CookieOptions configures the cookie jar shared by the http steps of an execution.
+type CookieOptions struct {
+	Disabled bool         // Disables the cookie jar if true.
+	File     string       // File the cookie jar is loaded from and saved to, relative to the configuration.
+	Seed     []CookieSeed // Cookies added to the jar before the execution.
+}
+
+// CookieSeed represents a cookie added to the jar before the execution.
+type CookieSeed struct {
+	Url   string // URL the cookie is sent to. Defaults to the base URL of the configuration.
+	Name  string // Name of the cookie.
+	Value string // Expression that evaluates to the value of the cookie.
+}
+
+func (c *CookieOptions) UnmarshalYAML(value *yaml.Node) error {
+	type rawCookieOptions CookieOptions
+
+	var raw rawCookieOptions
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+
+	if raw.File != "" {
+		file, err := filepath.Abs(raw.File)
+		if err != nil {
+			return fmt.Errorf("cookies: %v", err)
+		}
+
+		raw.File = file
+	}
+
+	for _, seed := range raw.Seed {
+		vErr := ValidationError{}
+
+		if seed.Name == "" {
+			vErr.Add(RequiredFieldError{Field: "name"})
+		}
+
+		if seed.Value == "" {
+			vErr.Add(RequiredFieldError{Field: "value"})
+		}
+
+		if vErr.HasError() {
+			return fmt.Errorf("cookies: seed: %w", &vErr)
+		}
+	}
+
+	*c = CookieOptions(raw)
+
+	return nil
+}
+
+// NewJar returns the cookie jar for the execution, or nil if the jar is disabled.
+// The jar is loaded from c.File if it exists, and the seed cookies are added with their values evaluated using variables.
+// Seed cookies without a URL are sent to baseUrl.
+func (c *CookieOptions) NewJar(baseUrl string, variables map[string]any) (*http.Jar, error) {
+	jar := http.NewJar()
+
+	if c == nil {
+		return jar, nil
+	}
+
+	if c.Disabled {
+		return nil, nil
+	}
+
+	if c.File != "" {
+		if err := jar.Load(c.File); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, seed := range c.Seed {
+		rawURL := seed.Url
+		if rawURL == "" {
+			rawURL = baseUrl
+		}
+
+		u, err := url.Parse(rawURL)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("cookie '%s': invalid url '%s'", seed.Name, rawURL)
+		}
+
+		val, err := expr.Eval(seed.Value, variables)
+		if err != nil {
+			return nil, fmt.Errorf("cookie '%s': invalid expression '%s' for value: %v", seed.Name, seed.Value, err)
+		}
+
+		jar.SetCookies(u, []*nethttp.Cookie{{Name: seed.Name, Value: fmt.Sprint(val), Path: "/"}})
+	}
+
+	return jar, nil
+}
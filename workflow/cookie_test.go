@@ -0,0 +1,107 @@
+package workflow
+
+import (
+	"bytes"
+	"fmt"
+	nethttp "net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/santhanuv/srotas/internal/http"
+	"github.com/santhanuv/srotas/internal/log"
+	"github.com/santhanuv/srotas/internal/store"
+)
+
+func TestCookieOptions_NewJar(t *testing.T) {
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if r.URL.Path == "/login" {
+			nethttp.SetCookie(w, &nethttp.Cookie{Name: "session", Value: "abc", Path: "/", MaxAge: 3600})
+		}
+
+		var sent []string
+		for _, cookie := range r.Cookies() {
+			sent = append(sent, cookie.Name+"="+cookie.Value)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"sent": "` + fmt.Sprint(sent) + `"}`))
+	}))
+	defer server.Close()
+
+	file := filepath.Join(t.TempDir(), "cookies.json")
+	opts := &CookieOptions{
+		File: file,
+		Seed: []CookieSeed{{Name: "theme", Value: "theme"}},
+	}
+
+	jar, err := opts.NewJar(server.URL, map[string]any{"theme": "dark"})
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	client := http.NewClient(0)
+	client.SetJar(jar)
+
+	s := store.NewStore(nil)
+	logBuf := bytes.NewBuffer(nil)
+
+	context, err := NewExecutionContext(
+		WithStore(s),
+		WithHttpClient(client),
+		WithCookieJar(jar),
+		WithLogger(log.New(logBuf, logBuf, logBuf)))
+	if err != nil {
+		t.Fatalf("failed to setup test: unable to create execution context")
+	}
+
+	def := Definition{
+		BaseUrl: server.URL,
+		Steps: StepList{
+			&Request{Type: "http", StepName: "login", Url: server.URL + "/login", Method: "GET"},
+			&Set{Type: "set", StepName: "shadow jar", Variables: map[string]string{"cookies": "'replaced'"}},
+			&Request{Type: "http", StepName: "me", Url: server.URL + "/me", Method: "GET", Store: map[string]string{
+				"sent":    "response.sent",
+				"session": "cookies.Get('session')",
+			}},
+		},
+	}
+
+	if err := Execute(&def, context); err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	if got, _ := s.Get("sent"); got != "[theme=dark session=abc]" {
+		t.Errorf("expected the seeded and received cookies to be sent but got %v", got)
+	}
+
+	if got, _ := s.Get("session"); got != "abc" {
+		t.Errorf("expected 'session' to be %q but got %v", "abc", got)
+	}
+
+	if got, _ := s.Get("cookies"); got != "replaced" {
+		t.Errorf("expected the cookie jar to be kept out of the variables but got %v", got)
+	}
+
+	if err := jar.Save(file); err != nil {
+		t.Fatalf("expected cookie jar to be saved but got %v", err)
+	}
+
+	loaded, err := (&CookieOptions{File: file}).NewJar("", nil)
+	if err != nil {
+		t.Fatalf("expected cookie jar to be loaded but got %v", err)
+	}
+
+	values, err := loaded.For(server.URL + "/me")
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	if values["session"] != "abc" || values["theme"] != "dark" {
+		t.Errorf("expected saved cookies to be loaded but got %v", values)
+	}
+
+	if jar, err := (&CookieOptions{Disabled: true}).NewJar(server.URL, nil); jar != nil || err != nil {
+		t.Errorf("expected no cookie jar when disabled but got (%v, %v)", jar, err)
+	}
+}
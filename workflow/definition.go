@@ -2,17 +2,25 @@ package workflow
 
 import (
 	"fmt"
+	"slices"
+
+	"github.com/expr-lang/expr"
 )
 
 // Definition represents the configuration structure that is unmarshalled from the config file.
 type Definition struct {
-	Version   string            // The configuration version used for execution.
-	BaseUrl   string            `yaml:"base_url"` // The base URL applied to all HTTP requests.
-	Timeout   uint              // The maximum time (in ms) allowed for HTTP requests.
-	Variables map[string]string // Predefined variables available during execution.
-	Headers   Header            // Global headers added to all HTTP requests.
-	Steps     StepList          // The sequence of steps to be executed.
-	Output    map[string]string // Defines variables to be included in the output.
+	Version       string               // The configuration version used for execution.
+	BaseUrl       string               `yaml:"base_url"` // The base URL applied to all HTTP requests.
+	Timeout       uint                 // The maximum time (in ms) allowed for HTTP requests.
+	MaxIterations uint                 `yaml:"max_iterations"` // The maximum number of iterations of loop steps without their own limit.
+	Variables     map[string]string    // Predefined variables available during execution.
+	Headers       Header               // Global headers added to all HTTP requests.
+	Cookies       *CookieOptions       // Options of the cookie jar shared by HTTP requests.
+	Setup         StepList             // Steps executed before Steps.
+	Steps         StepList             // The sequence of steps to be executed.
+	Teardown      StepList             // Steps always executed after Steps, even if the execution fails.
+	Functions     map[string]*Function // Named sequences of steps invoked by call steps.
+	Output        map[string]string    // Defines variables to be included in the output.
 	// If true, all variables in ExecutionContext are included in the output.
 	OutputAll bool `yaml:"output_all"`
 }
@@ -27,3 +35,62 @@ func (d *Definition) Validate() error {
 
 	return nil
 }
+
+// stepLists returns the step lists of the definition, including the steps of its functions.
+func (d *Definition) stepLists() []StepList {
+	lists := []StepList{d.Setup, d.Steps, d.Teardown}
+
+	for _, function := range d.Functions {
+		lists = append(lists, function.Steps)
+	}
+
+	return lists
+}
+
+// EvalOutput evaluates the output expressions of the definition using vars as the environment.
+// If OutputAll is true, all variables are returned.
+func (d *Definition) EvalOutput(vars map[string]any) (map[string]any, error) {
+	if d.OutputAll {
+		return vars, nil
+	}
+
+	oVars := make(map[string]any, len(d.Output))
+	for vn, ve := range d.Output {
+		val, err := expr.Eval(ve, vars)
+
+		if err != nil {
+			return nil, fmt.Errorf("output '%s': %v", vn, err)
+		}
+
+		oVars[vn] = val
+	}
+
+	return oVars, nil
+}
+
+// EvalPartialOutput evaluates the output expressions of the definition like [Definition.EvalOutput],
+// omitting the outputs whose expressions cannot be evaluated, such as those referring to variables
+// that are not set because the execution was interrupted. The names of the omitted outputs are returned.
+func (d *Definition) EvalPartialOutput(vars map[string]any) (map[string]any, []string) {
+	if d.OutputAll {
+		return vars, nil
+	}
+
+	oVars := make(map[string]any, len(d.Output))
+	var omitted []string
+
+	for vn, ve := range d.Output {
+		val, err := expr.Eval(ve, vars)
+
+		if err != nil {
+			omitted = append(omitted, vn)
+			continue
+		}
+
+		oVars[vn] = val
+	}
+
+	slices.Sort(omitted)
+
+	return oVars, omitted
+}
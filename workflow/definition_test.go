@@ -2,6 +2,7 @@ package workflow
 
 import (
 	"errors"
+	"reflect"
 	"testing"
 )
 
@@ -58,3 +59,23 @@ func TestDefinition_Validate(t *testing.T) {
 
 	}
 }
+
+func TestDefinition_EvalPartialOutput(t *testing.T) {
+	def := &Definition{
+		Output: map[string]string{
+			"user":  "user",
+			"order": "order.id",
+			"total": "total",
+		},
+	}
+
+	output, omitted := def.EvalPartialOutput(map[string]any{"user": "john"})
+
+	if want := map[string]any{"user": "john", "total": nil}; !reflect.DeepEqual(output, want) {
+		t.Errorf("expected output %v but got %v", want, output)
+	}
+
+	if want := []string{"order"}; !reflect.DeepEqual(omitted, want) {
+		t.Errorf("expected omitted outputs %v but got %v", want, omitted)
+	}
+}
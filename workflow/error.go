@@ -1,8 +1,11 @@
 package workflow
 
 import (
+	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 )
 
 // RequiredFieldError represents an error that occurs when a required field is missing.
@@ -40,3 +43,95 @@ func (v *ValidationError) Add(err error) {
 func (v *ValidationError) HasError() bool {
 	return len(v.errs) > 0
 }
+
+// ExecutionError represents the failure of the execution of a [Definition].
+// It holds the error of the setup or main steps separately from the error of the teardown steps.
+type ExecutionError struct {
+	Err         error // Error returned by the setup or main steps, if any.
+	TeardownErr error // Error returned by the teardown steps, if any.
+}
+
+func (e *ExecutionError) Error() string {
+	switch {
+	case e.TeardownErr == nil:
+		return e.Err.Error()
+	case e.Err == nil:
+		return fmt.Sprintf("teardown failed: %v", e.TeardownErr)
+	}
+
+	return fmt.Sprintf("%v\nteardown also failed: %v", e.Err, e.TeardownErr)
+}
+
+func (e *ExecutionError) Unwrap() []error {
+	var errs []error
+
+	for _, err := range []error{e.Err, e.TeardownErr} {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// StepError represents an error returned by a step during execution.
+// It identifies the innermost step that failed, while its message is the message of the underlying error.
+type StepError struct {
+	Step string // Name of the step that failed.
+	Err  error  // Error returned by the step.
+}
+
+func (s *StepError) Error() string {
+	return s.Err.Error()
+}
+
+func (s *StepError) Unwrap() error {
+	return s.Err
+}
+
+// LoopLimitError represents the failure of a loop step that exceeded its maximum number of iterations or duration.
+type LoopLimitError struct {
+	Step          string         // Name of the loop step.
+	Iterations    uint           // Number of iterations started before the limit was exceeded.
+	Elapsed       time.Duration  // Time elapsed since the loop started.
+	MaxIterations uint           // Maximum number of iterations allowed, zero if the iterations were not exceeded.
+	MaxDuration   time.Duration  // Maximum duration allowed, zero if the duration was not exceeded.
+	Variables     map[string]any // Values of the loop variables when the limit was exceeded.
+}
+
+func (l *LoopLimitError) Error() string {
+	var b strings.Builder
+
+	if l.MaxIterations != 0 {
+		fmt.Fprintf(&b, "loop step '%s' exceeded max_iterations of %d", l.Step, l.MaxIterations)
+	} else {
+		fmt.Fprintf(&b, "loop step '%s' exceeded max_duration of %s", l.Step, l.MaxDuration)
+	}
+
+	fmt.Fprintf(&b, " after %d iteration(s) in %s", l.Iterations, l.Elapsed.Round(time.Millisecond))
+
+	if len(l.Variables) == 0 {
+		return b.String()
+	}
+
+	b.WriteString("; loop variables:")
+
+	names := make([]string, 0, len(l.Variables))
+	for name := range l.Variables {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		val := l.Variables[name]
+
+		if data, err := json.Marshal(val); err == nil {
+			fmt.Fprintf(&b, " %s=%s", name, data)
+		} else {
+			fmt.Fprintf(&b, " %s=%v", name, val)
+		}
+	}
+
+	return b.String()
+}
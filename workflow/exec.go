@@ -0,0 +1,211 @@
+package workflow
+
+import (
+	"bytes"
+	ctx "context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/expr-lang/expr"
+)
+
+// Exec represents a step that runs a local command.
+// The result of the command is available as the result variable while evaluating the validations and Store.
+// The result is a map with the stdout, stderr and exit_code of the command, where stdout is parsed as JSON if ParseJson is true.
+type Exec struct {
+	Type        string            // The type of the step.
+	StepName    string            `yaml:"name"` // Identifier for the step.
+	Command     []string          // The command to run followed by its fixed arguments.
+	Args        []string          // Expressions evaluated and appended to the arguments of the command.
+	Env         map[string]string // Environment variables mapped to expressions, added to the environment of the command.
+	Dir         string            // Working directory of the command, relative to the configuration.
+	Stdin       string            // Expression whose result is written to the standard input of the command.
+	Timeout     uint              // Maximum time (milliseconds) allowed for the command.
+	ParseJson   bool              `yaml:"parse_json"` // Parses stdout as JSON if true.
+	Store       map[string]string // Variables mapped to expressions evaluated using the result.
+	Validations *ExecValidator    // Validation rules for the result.
+}
+
+// ExecValidator represents the validations for the result of an [Exec] step.
+// If ExitCode is not provided, the command is expected to exit with code 0.
+type ExecValidator struct {
+	ExitCode *int     `yaml:"exit_code"` // Expected exit code of the command.
+	Asserts  []Assert // Assert expr expressions on the result.
+}
+
+// Validate checks the fields of the [Exec] step and returns a list of validation errors, if any.
+func (e *Exec) Validate() error {
+	vErr := ValidationError{}
+
+	if e.StepName == "" {
+		vErr.Add(RequiredFieldError{Field: "name"})
+	}
+
+	if len(e.Command) == 0 || e.Command[0] == "" {
+		vErr.Add(RequiredFieldError{Field: "command"})
+	}
+
+	if vErr.HasError() {
+		return fmt.Errorf("exec step: %w", &vErr)
+	}
+
+	return nil
+}
+
+func (e *Exec) Name() string {
+	return e.StepName
+}
+
+// Execute executes the step with the specified context.
+func (e *Exec) Execute(context *ExecutionContext) error {
+	vars := context.variables()
+
+	args := slices.Clone(e.Command[1:])
+	for idx, ae := range e.Args {
+		val, err := expr.Eval(ae, vars)
+		if err != nil {
+			return fmt.Errorf("exec step '%s': invalid expression '%s' for args[%d]: %v", e.StepName, ae, idx, err)
+		}
+
+		args = append(args, fmt.Sprint(val))
+	}
+
+	env := os.Environ()
+	for name, ee := range e.Env {
+		val, err := expr.Eval(ee, vars)
+		if err != nil {
+			return fmt.Errorf("exec step '%s': invalid expression '%s' for env '%s': %v", e.StepName, ee, name, err)
+		}
+
+		env = append(env, fmt.Sprintf("%s=%v", name, val))
+	}
+
+	var stdin []byte
+
+	if e.Stdin != "" {
+		val, err := expr.Eval(e.Stdin, vars)
+		if err != nil {
+			return fmt.Errorf("exec step '%s': invalid expression '%s' for stdin: %v", e.StepName, e.Stdin, err)
+		}
+
+		stdin, err = toBytes(val)
+		if err != nil {
+			return fmt.Errorf("exec step '%s': stdin: %v", e.StepName, err)
+		}
+	}
+
+	cmdCtx := context.ctx
+
+	if e.Timeout != 0 {
+		var cancel ctx.CancelFunc
+		cmdCtx, cancel = ctx.WithTimeout(cmdCtx, time.Duration(e.Timeout)*time.Millisecond)
+		defer cancel()
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(cmdCtx, e.Command[0], args...)
+	cmd.Env = env
+	cmd.Dir = e.Dir
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	context.logger.Info("running command '%s' for step '%s'", strings.Join(cmd.Args, " "), e.StepName)
+
+	err := cmd.Run()
+
+	if reason := context.Interrupted(); reason != nil {
+		return fmt.Errorf("exec step '%s': command aborted: %w", e.StepName, reason)
+	}
+
+	if errors.Is(cmdCtx.Err(), ctx.DeadlineExceeded) {
+		return fmt.Errorf("exec step '%s': command timed out after %dms", e.StepName, e.Timeout)
+	}
+
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return fmt.Errorf("exec step '%s': %v", e.StepName, err)
+	}
+
+	result := map[string]any{
+		"stdout":    stdout.String(),
+		"stderr":    stderr.String(),
+		"exit_code": cmd.ProcessState.ExitCode(),
+	}
+
+	if err := e.checkExitCode(cmd.ProcessState.ExitCode()); err != nil {
+		return fmt.Errorf("exec step '%s': %v\nstderr: %s", e.StepName, err, stderr.String())
+	}
+
+	if e.ParseJson {
+		var out any
+		if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+			return fmt.Errorf("exec step '%s': failed to parse stdout as json: %v", e.StepName, err)
+		}
+
+		result["stdout"] = out
+	}
+
+	rVars := context.variables()
+	rVars["result"] = result
+
+	if e.Validations != nil {
+		for _, assert := range e.Validations.Asserts {
+			if err := assert.Validate(rVars, nil); err != nil {
+				return fmt.Errorf("exec step '%s': %v", e.StepName, err)
+			}
+		}
+	}
+
+	newVars := make(map[string]any, len(e.Store))
+
+	for vn, ve := range e.Store {
+		val, err := expr.Eval(ve, rVars)
+		if err != nil {
+			return fmt.Errorf("exec step '%s': invalid expression '%s' for variable '%s': %v", e.StepName, ve, vn, err)
+		}
+
+		newVars[vn] = val
+	}
+
+	context.store.Add(newVars)
+
+	context.logger.Debug("successfully executed exec step '%s'", e.StepName)
+
+	return nil
+}
+
+// checkExitCode returns an error if code is not the expected exit code.
+func (e *Exec) checkExitCode(code int) error {
+	expected := 0
+	if e.Validations != nil && e.Validations.ExitCode != nil {
+		expected = *e.Validations.ExitCode
+	}
+
+	if code != expected {
+		return fmt.Errorf("exit code: expected '%d' but got '%d'", expected, code)
+	}
+
+	return nil
+}
+
+// toBytes returns strings and byte slices as is, and other values encoded as JSON.
+func toBytes(val any) ([]byte, error) {
+	switch v := val.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	}
+
+	return json.Marshal(val)
+}
@@ -0,0 +1,171 @@
+package workflow
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/santhanuv/srotas/internal/log"
+	"github.com/santhanuv/srotas/internal/store"
+	"gopkg.in/yaml.v3"
+)
+
+func TestExec_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		exec Exec
+		err  bool
+	}{
+		{
+			name: "'name' is not provided",
+			exec: Exec{
+				Type:    "exec",
+				Command: []string{"echo"},
+			},
+			err: true,
+		},
+		{
+			name: "'command' is not provided",
+			exec: Exec{
+				Type:     "exec",
+				StepName: "name",
+			},
+			err: true,
+		},
+		{
+			name: "All required fields are provided",
+			exec: Exec{
+				Type:     "exec",
+				StepName: "name",
+				Command:  []string{"echo"},
+			},
+			err: false,
+		},
+	}
+
+	for _, tt := range tests {
+		err := tt.exec.Validate()
+
+		if !tt.err && err != nil {
+			t.Errorf("in test %q; expected no error but got %q", tt.name, err)
+			continue
+		}
+
+		if tt.err {
+			if err == nil {
+				t.Errorf("in test %q; expected error but got none", tt.name)
+				continue
+			}
+
+			var target *ValidationError
+			if !errors.As(err, &target) {
+				t.Errorf("in test %q; got %q; want validation error", tt.name, err)
+			}
+		}
+	}
+}
+
+func TestExec_Execute(t *testing.T) {
+	tests := []struct {
+		name string
+		step string
+		want map[string]any
+		err  string
+	}{
+		{
+			name: "stdout, stderr and exit code are stored",
+			step: `
+name: run
+command: ["sh", "-c", "echo $GREETING $0; echo oops >&2"]
+args: ["user"]
+env:
+  GREETING: "'hello'"
+store:
+  out: "result.stdout"
+  errOut: "result.stderr"
+  code: "result.exit_code"
+`,
+			want: map[string]any{"out": "hello srotas\n", "errOut": "oops\n", "code": 0},
+		},
+		{
+			name: "stdin is written and stdout is parsed as json",
+			step: `
+name: run
+command: ["cat"]
+stdin: "{id: 1}"
+parse_json: true
+store:
+  id: "result.stdout.id"
+`,
+			want: map[string]any{"id": float64(1)},
+		},
+		{
+			name: "unexpected exit code",
+			step: `
+name: run
+command: ["sh", "-c", "exit 3"]
+`,
+			err: "exit code: expected '0' but got '3'",
+		},
+		{
+			name: "expected exit code and asserts",
+			step: `
+name: run
+command: ["sh", "-c", "echo failed; exit 3"]
+validations:
+  exit_code: 3
+  asserts:
+    - "trim(result.stdout) == 'failed'"
+store:
+  code: "result.exit_code"
+`,
+			want: map[string]any{"code": 3},
+		},
+		{
+			name: "command times out",
+			step: `
+name: run
+command: ["sleep", "1"]
+timeout: 50
+`,
+			err: "command timed out after 50ms",
+		},
+	}
+
+	for _, tt := range tests {
+		var step Exec
+		if err := yaml.Unmarshal([]byte(tt.step), &step); err != nil {
+			t.Fatalf("in test %q; failed to setup test: %v", tt.name, err)
+		}
+
+		s := store.NewStore(map[string]any{"user": "srotas"})
+		logBuf := bytes.NewBuffer(nil)
+
+		context, err := NewExecutionContext(WithStore(s), WithLogger(log.New(logBuf, logBuf, logBuf)))
+		if err != nil {
+			t.Fatalf("failed to setup test: unable to create execution context")
+		}
+
+		err = step.Execute(context)
+
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("in test %q; expected error containing %q but got %v", tt.name, tt.err, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("in test %q; expected no error but got %q", tt.name, err)
+			continue
+		}
+
+		for name, want := range tt.want {
+			if got, _ := s.Get(name); !reflect.DeepEqual(got, want) {
+				t.Errorf("in test %q; got %#v for '%s'; want %#v", tt.name, got, name, want)
+			}
+		}
+	}
+}
@@ -1,24 +1,57 @@
 package workflow
 
 import (
+	ctx "context"
+	"errors"
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/santhanuv/srotas/internal/http"
 	"github.com/santhanuv/srotas/internal/log"
+	"github.com/santhanuv/srotas/internal/prompt"
 	"github.com/santhanuv/srotas/internal/store"
 )
 
 // ExecutionContext holds contextual data for the execution of a config.
 // It manages execution-specific state, including variables, headers, and other necessary metadata.
 type ExecutionContext struct {
-	httpClient    HttpClient     // Client used for the execution of http request.
-	store         *store.Store   // Store used in the config execution.
-	globalOptions *ConfigOptions // Global options for config execution.
-	logger        *log.Logger    // Logger used in the config execution.
+	httpClient    HttpClient          // Client used for the execution of http request.
+	store         *store.Store        // Store used in the config execution.
+	globalOptions *ConfigOptions      // Global options for config execution.
+	logger        *log.Logger         // Logger used in the config execution.
+	lastResponse  any                 // Body of the last http response received in the config execution.
+	ctx           ctx.Context         // Context of the config execution, cancelled when the execution is interrupted.
+	cancel        ctx.CancelCauseFunc // Cancels ctx with the reason for the interruption.
+	prompter      Prompter            // Prompter used to ask the user for values in prompt steps.
+	maxIterations uint                // Default maximum number of iterations of loop steps.
+	cookieJar     *http.Jar           // Cookie jar shared by the http requests, if enabled.
 }
 
+// ErrInterrupted is the reason for interrupting the execution when it is not otherwise specified.
+var ErrInterrupted = errors.New("execution interrupted")
+
+// HttpClient sends the http requests of the config execution.
+// The request should be aborted once the given context is cancelled.
 type HttpClient interface {
-	Do(*http.Request) (*http.Response, error)
+	Do(ctx.Context, *http.Request) (*http.Response, error)
+}
+
+// DefaultMaxIterations is the maximum number of iterations of loop steps when it is not otherwise specified.
+const DefaultMaxIterations = 10000
+
+// Prompter asks questions to the user for prompt steps.
+// Ask should return once its context is done, so that an interrupted execution is not blocked by a question.
+type Prompter interface {
+	Ask(ctx.Context, prompt.Question) (string, error)
+}
+
+// Logger writes the messages of steps to the log of the execution.
+// The format and args are interpreted as in [fmt.Printf].
+type Logger interface {
+	Debug(format string, args ...any)
+	Info(format string, args ...any)
+	Error(format string, args ...any)
 }
 
 // ConfigOptions defines execution settings for the configuration,
@@ -51,25 +84,110 @@ func NewExecutionContext(options ...ExecutionOption) (*ExecutionContext, error)
 		context.logger.SetDebugMode(true)
 	}
 
+	if context.ctx == nil {
+		context.ctx = ctx.Background()
+	}
+
+	context.ctx, context.cancel = ctx.WithCancelCause(context.ctx)
+
+	if context.globalOptions == nil {
+		context.globalOptions = &ConfigOptions{}
+	}
+
 	if context.httpClient == nil {
 		context.httpClient = http.NewClient(15000)
 	}
 
+	if context.prompter == nil {
+		context.prompter = prompt.NewTerminal(os.Stdin, os.Stderr)
+	}
+
+	if context.maxIterations == 0 {
+		context.maxIterations = DefaultMaxIterations
+	}
+
 	return &context, nil
 }
 
 // Execute executes the given definition with the specified context.
+// The setup steps are executed first, followed by the main steps if the setup succeeds.
+// The teardown steps are always executed afterwards, even if the execution fails or is interrupted.
+// If the main or teardown steps fail, the returned error is an [ExecutionError].
 func Execute(definition *Definition, context *ExecutionContext) error {
-	steps := definition.Steps
+	err := executeSteps(definition.Setup, context)
+	if err != nil {
+		err = fmt.Errorf("setup failed: %w", err)
+	} else {
+		err = executeSteps(definition.Steps, context)
+	}
 
-	for _, step := range steps {
-		err := step.Execute(context)
-		if err != nil {
-			return err
-		}
+	var tErr error
+
+	if definition.Teardown != nil {
+		context.logger.Debug("executing teardown...")
+		tErr = executeSteps(definition.Teardown, context.uninterruptible())
+	}
+
+	if err == nil && tErr == nil {
+		return nil
+	}
+
+	return &ExecutionError{Err: err, TeardownErr: tErr}
+}
+
+// Interrupt stops the execution with the given reason as the error.
+// In-flight requests, commands, delays and loops are aborted, and no further steps are executed.
+// Teardown steps are executed regardless of the interruption.
+func (e *ExecutionContext) Interrupt(reason error) {
+	if reason == nil {
+		reason = ErrInterrupted
+	}
+
+	e.cancel(reason)
+}
+
+// Interrupted returns the reason for the interruption, or nil if the execution is not interrupted.
+func (e *ExecutionContext) Interrupted() error {
+	if e.ctx.Err() == nil {
+		return nil
+	}
+
+	return ctx.Cause(e.ctx)
+}
+
+// uninterruptible returns a copy of the [ExecutionContext] that is not affected by interruptions of e.
+func (e *ExecutionContext) uninterruptible() *ExecutionContext {
+	u := *e
+	u.ctx, u.cancel = ctx.WithCancelCause(ctx.WithoutCancel(e.ctx))
+
+	return &u
+}
+
+// sleep waits for the duration d, returning early with the reason for the interruption if the execution is interrupted.
+func (e *ExecutionContext) sleep(d time.Duration) error {
+	if d <= 0 {
+		return e.Interrupted()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-e.ctx.Done():
+		return e.Interrupted()
 	}
+}
+
+// WithContext configures the [ExecutionContext] with the specified context.
+// Cancelling ctx interrupts the execution.
+func WithContext(c ctx.Context) ExecutionOption {
+	return func(context *ExecutionContext) error {
+		context.ctx = c
 
-	return nil
+		return nil
+	}
 }
 
 // WithGlobalOptions configures the [ExecutionContext] with the baseUrl and headers.
@@ -95,6 +213,35 @@ func WithHttpClient(client HttpClient) ExecutionOption {
 	}
 }
 
+// WithPrompter configures the [ExecutionContext] with the specified prompter.
+func WithPrompter(prompter Prompter) ExecutionOption {
+	return func(context *ExecutionContext) error {
+		context.prompter = prompter
+
+		return nil
+	}
+}
+
+// WithMaxIterations configures the [ExecutionContext] with the default maximum number of iterations of loop steps.
+// If n is zero, [DefaultMaxIterations] is used.
+func WithMaxIterations(n uint) ExecutionOption {
+	return func(context *ExecutionContext) error {
+		context.maxIterations = n
+
+		return nil
+	}
+}
+
+// WithCookieJar configures the [ExecutionContext] with the cookie jar used by the http client,
+// making it available to expressions as the cookies variable.
+func WithCookieJar(jar *http.Jar) ExecutionOption {
+	return func(context *ExecutionContext) error {
+		context.cookieJar = jar
+
+		return nil
+	}
+}
+
 // WithLogger configures the [ExecutionContext] with the specified logger.
 func WithLogger(logger *log.Logger) ExecutionOption {
 	return func(context *ExecutionContext) error {
@@ -113,7 +260,54 @@ func WithStore(store *store.Store) ExecutionOption {
 	}
 }
 
+// fork returns a copy of the [ExecutionContext] that uses the given store and logger.
+// The http client and global options are shared with e.
+func (e *ExecutionContext) fork(s *store.Store, logger *log.Logger) *ExecutionContext {
+	forked := *e
+	forked.store = s
+	forked.logger = logger
+
+	return &forked
+}
+
+// cookiesVariable is the name of the cookie jar in expressions.
+// The jar is not stored as a variable, so it cannot be replaced by steps and is not part of the output.
+const cookiesVariable = "cookies"
+
+// variables returns the variables available to expressions, which are the variables in the store
+// and the cookie jar, if enabled. The cookie jar hides a variable with the same name.
+func (e *ExecutionContext) variables() map[string]any {
+	vars := e.store.Map()
+
+	if e.cookieJar != nil {
+		vars[cookiesVariable] = e.cookieJar
+	}
+
+	return vars
+}
+
 // Variables returns all the variables in the store as a map.
 func (e *ExecutionContext) Variables() map[string]any {
 	return e.store.Map()
 }
+
+// SetVariable sets the variable name to value in the store, making it available to the following steps.
+func (e *ExecutionContext) SetVariable(name string, value any) {
+	e.store.Set(name, value)
+}
+
+// RemoveVariable removes the variable name from the store.
+func (e *ExecutionContext) RemoveVariable(name string) {
+	e.store.Remove(name)
+}
+
+// Context returns the context of the execution, which is cancelled when the execution is interrupted.
+// Steps that block, for example on network calls, should stop once it is done.
+func (e *ExecutionContext) Context() ctx.Context {
+	return e.ctx
+}
+
+// Logger returns the logger used in the execution.
+func (e *ExecutionContext) Logger() Logger {
+	return e.logger
+}
@@ -0,0 +1,173 @@
+package workflow
+
+import (
+	"bytes"
+	ctx "context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/santhanuv/srotas/internal/log"
+	"github.com/santhanuv/srotas/internal/store"
+)
+
+// interruptStep is a test step that interrupts the execution when executed.
+type interruptStep struct{}
+
+func (i *interruptStep) Execute(context *ExecutionContext) error {
+	context.Interrupt(nil)
+	return nil
+}
+
+func (i *interruptStep) Validate() error { return nil }
+
+func (i *interruptStep) Name() string { return "interrupt" }
+
+func TestExecute_Teardown(t *testing.T) {
+	mainErr := errors.New("main failed")
+	teardownErr := errors.New("teardown failed")
+
+	tests := []struct {
+		name        string
+		def         Definition
+		mainErr     error
+		teardownErr error
+		executed    []string
+	}{
+		{
+			name: "teardown is executed after success",
+			def: Definition{
+				Setup:    StepList{&storeStep{vars: map[string]any{"setup": true}}},
+				Steps:    StepList{&storeStep{vars: map[string]any{"main": true}}},
+				Teardown: StepList{&storeStep{vars: map[string]any{"teardown": true}}},
+			},
+			executed: []string{"setup", "main", "teardown"},
+		},
+		{
+			name: "teardown is executed after setup failure",
+			def: Definition{
+				Setup:    StepList{&failStep{name: "setup", err: mainErr}},
+				Steps:    StepList{&storeStep{vars: map[string]any{"main": true}}},
+				Teardown: StepList{&storeStep{vars: map[string]any{"teardown": true}}},
+			},
+			mainErr:  mainErr,
+			executed: []string{"teardown"},
+		},
+		{
+			name: "main and teardown failures are reported",
+			def: Definition{
+				Steps:    StepList{&failStep{name: "main", err: mainErr}},
+				Teardown: StepList{&failStep{name: "teardown", err: teardownErr}},
+			},
+			mainErr:     mainErr,
+			teardownErr: teardownErr,
+		},
+		{
+			name: "teardown is executed after interruption",
+			def: Definition{
+				Steps:    StepList{&interruptStep{}, &storeStep{vars: map[string]any{"main": true}}},
+				Teardown: StepList{&storeStep{vars: map[string]any{"teardown": true}}},
+			},
+			mainErr:  ErrInterrupted,
+			executed: []string{"teardown"},
+		},
+	}
+
+	for _, tt := range tests {
+		s := store.NewStore(nil)
+		logBuf := bytes.NewBuffer(nil)
+
+		context, err := NewExecutionContext(WithStore(s), WithLogger(log.New(logBuf, logBuf, logBuf)))
+		if err != nil {
+			t.Fatalf("failed to setup test: unable to create execution context")
+		}
+
+		err = Execute(&tt.def, context)
+
+		if tt.mainErr == nil && tt.teardownErr == nil {
+			if err != nil {
+				t.Errorf("in test %q; expected no error but got %q", tt.name, err)
+			}
+		} else {
+			var execErr *ExecutionError
+			if !errors.As(err, &execErr) {
+				t.Errorf("in test %q; expected execution error but got %v", tt.name, err)
+				continue
+			}
+
+			if !errors.Is(execErr.Err, tt.mainErr) || !errors.Is(execErr.TeardownErr, tt.teardownErr) {
+				t.Errorf("in test %q; expected errors (%v, %v) but got (%v, %v)", tt.name, tt.mainErr, tt.teardownErr, execErr.Err, execErr.TeardownErr)
+			}
+		}
+
+		for _, name := range tt.executed {
+			if _, ok := s.Get(name); !ok {
+				t.Errorf("in test %q; expected %s steps to be executed", tt.name, name)
+			}
+		}
+
+		if len(s.Map()) != len(tt.executed) {
+			t.Errorf("in test %q; expected only %v steps to be executed but got %v", tt.name, tt.executed, s.Map())
+		}
+	}
+}
+
+func TestExecute_Cancellation(t *testing.T) {
+	reason := errors.New("stopped by test")
+
+	tests := []struct {
+		name   string
+		step   Step
+		cancel func(context *ExecutionContext, cancel ctx.CancelFunc)
+		err    error
+	}{
+		{
+			name: "interrupt aborts a delayed request",
+			step: &Request{Type: "http", StepName: "delayed", Url: "http://localhost", Method: "GET", Delay: 5000},
+			cancel: func(context *ExecutionContext, cancel ctx.CancelFunc) {
+				context.Interrupt(reason)
+			},
+			err: reason,
+		},
+		{
+			name: "cancelling the context aborts a command",
+			step: &Exec{Type: "exec", StepName: "sleep", Command: []string{"sleep", "5"}},
+			cancel: func(context *ExecutionContext, cancel ctx.CancelFunc) {
+				cancel()
+			},
+			err: ctx.Canceled,
+		},
+		{
+			name: "interrupt aborts a loop",
+			step: &While{Type: "while", StepName: "forever", Condition: "true", MaxIterations: 1 << 30, Body: StepList{}},
+			cancel: func(context *ExecutionContext, cancel ctx.CancelFunc) {
+				context.Interrupt(reason)
+			},
+			err: reason,
+		},
+	}
+
+	for _, tt := range tests {
+		parent, cancel := ctx.WithCancel(ctx.Background())
+		logBuf := bytes.NewBuffer(nil)
+
+		context, err := NewExecutionContext(WithContext(parent), WithLogger(log.New(logBuf, logBuf, logBuf)))
+		if err != nil {
+			t.Fatalf("failed to setup test: unable to create execution context")
+		}
+
+		time.AfterFunc(50*time.Millisecond, func() { tt.cancel(context, cancel) })
+
+		start := time.Now()
+		err = Execute(&Definition{Steps: StepList{tt.step}}, context)
+		cancel()
+
+		if !errors.Is(err, tt.err) {
+			t.Errorf("in test %q; expected error %v but got %v", tt.name, tt.err, err)
+		}
+
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("in test %q; expected the step to be aborted but it took %s", tt.name, elapsed)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+package workflow
+
+// unregisterStep removes the step type registered with [RegisterStep], so that tests can register it again.
+func unregisterStep(stepType string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	delete(registry, stepType)
+}
@@ -0,0 +1,244 @@
+package workflow
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/expr-lang/expr"
+	"gopkg.in/yaml.v3"
+)
+
+// Formats of the files written by [WriteFile] and read by [ReadFile] steps.
+const (
+	JsonFormat = "json" // JSON document.
+	YamlFormat = "yaml" // YAML document.
+	CsvFormat  = "csv"  // CSV with a header row, read as a list of maps.
+	TextFormat = "text" // Raw text.
+)
+
+// WriteFile represents a step that writes the result of an expression to a file.
+// The Path is relative to the configuration, and missing parent directories are created.
+type WriteFile struct {
+	Type     string // The type of the step.
+	StepName string `yaml:"name"` // Identifier for the step.
+	Path     string // Path of the file to write.
+	Value    string // Expression whose result is written to the file.
+	Format   string // Format of the file. Inferred from the extension of Path if not provided.
+	Pretty   bool   // Indents JSON output if true.
+}
+
+// Validate checks the fields of the [WriteFile] step and returns a list of validation errors, if any.
+func (w *WriteFile) Validate() error {
+	vErr := ValidationError{}
+
+	if w.StepName == "" {
+		vErr.Add(RequiredFieldError{Field: "name"})
+	}
+
+	if w.Path == "" {
+		vErr.Add(RequiredFieldError{Field: "path"})
+	}
+
+	if w.Value == "" {
+		vErr.Add(RequiredFieldError{Field: "value"})
+	}
+
+	if formats := []string{JsonFormat, YamlFormat, TextFormat}; !slices.Contains(formats, w.format()) {
+		vErr.Add(fmt.Errorf("format should be one of %s", strings.Join(formats, ", ")))
+	}
+
+	if vErr.HasError() {
+		return fmt.Errorf("write_file step: %w", &vErr)
+	}
+
+	return nil
+}
+
+func (w *WriteFile) Name() string {
+	return w.StepName
+}
+
+// format returns the format of the file, inferred from the extension of Path if Format is not provided.
+// CSV files are written as text, since values cannot be encoded as CSV.
+func (w *WriteFile) format() string {
+	if w.Format != "" {
+		return w.Format
+	}
+
+	if format := formatOf(w.Path); format != CsvFormat {
+		return format
+	}
+
+	return TextFormat
+}
+
+// Execute executes the step with the specified context.
+func (w *WriteFile) Execute(context *ExecutionContext) error {
+	val, err := expr.Eval(w.Value, context.variables())
+	if err != nil {
+		return fmt.Errorf("write_file step '%s': invalid expression '%s' for value: %v", w.StepName, w.Value, err)
+	}
+
+	var data []byte
+	format := w.format()
+
+	switch format {
+	case JsonFormat:
+		if w.Pretty {
+			data, err = json.MarshalIndent(val, "", "  ")
+		} else {
+			data, err = json.Marshal(val)
+		}
+
+		data = append(data, '\n')
+	case YamlFormat:
+		data, err = yaml.Marshal(val)
+	default:
+		data, err = toBytes(val)
+	}
+
+	if err != nil {
+		return fmt.Errorf("write_file step '%s': failed to encode value as %s: %v", w.StepName, format, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(w.Path), 0o755); err != nil {
+		return fmt.Errorf("write_file step '%s': %v", w.StepName, err)
+	}
+
+	if err := os.WriteFile(w.Path, data, 0o644); err != nil {
+		return fmt.Errorf("write_file step '%s': %v", w.StepName, err)
+	}
+
+	context.logger.Debug("successfully executed write_file step '%s', wrote '%s'", w.StepName, w.Path)
+
+	return nil
+}
+
+// ReadFile represents a step that reads a file and stores its content in the variable named As.
+// The Path is relative to the configuration.
+type ReadFile struct {
+	Type     string // The type of the step.
+	StepName string `yaml:"name"` // Identifier for the step.
+	Path     string // Path of the file to read.
+	As       string // The variable name to store the content.
+	Format   string // Format of the file. Inferred from the extension of Path if not provided.
+}
+
+// Validate checks the fields of the [ReadFile] step and returns a list of validation errors, if any.
+func (r *ReadFile) Validate() error {
+	vErr := ValidationError{}
+
+	if r.StepName == "" {
+		vErr.Add(RequiredFieldError{Field: "name"})
+	}
+
+	if r.Path == "" {
+		vErr.Add(RequiredFieldError{Field: "path"})
+	}
+
+	if r.As == "" {
+		vErr.Add(RequiredFieldError{Field: "as"})
+	}
+
+	if formats := []string{JsonFormat, YamlFormat, CsvFormat, TextFormat}; !slices.Contains(formats, r.format()) {
+		vErr.Add(fmt.Errorf("format should be one of %s", strings.Join(formats, ", ")))
+	}
+
+	if vErr.HasError() {
+		return fmt.Errorf("read_file step: %w", &vErr)
+	}
+
+	return nil
+}
+
+func (r *ReadFile) Name() string {
+	return r.StepName
+}
+
+// format returns the format of the file, inferred from the extension of Path if Format is not provided.
+func (r *ReadFile) format() string {
+	if r.Format != "" {
+		return r.Format
+	}
+
+	return formatOf(r.Path)
+}
+
+// Execute executes the step with the specified context.
+func (r *ReadFile) Execute(context *ExecutionContext) error {
+	data, err := os.ReadFile(r.Path)
+	if err != nil {
+		return fmt.Errorf("read_file step '%s': %v", r.StepName, err)
+	}
+
+	var val any
+	format := r.format()
+
+	switch format {
+	case JsonFormat:
+		err = json.Unmarshal(data, &val)
+	case YamlFormat:
+		err = yaml.Unmarshal(data, &val)
+	case CsvFormat:
+		val, err = parseCsv(data)
+	default:
+		val = string(data)
+	}
+
+	if err != nil {
+		return fmt.Errorf("read_file step '%s': failed to parse '%s' as %s: %v", r.StepName, r.Path, format, err)
+	}
+
+	context.store.Set(r.As, val)
+
+	context.logger.Debug("successfully executed read_file step '%s', read '%s'", r.StepName, r.Path)
+
+	return nil
+}
+
+// parseCsv parses CSV data with a header row into a list of maps from the column names to the values.
+func parseCsv(data []byte) ([]any, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return []any{}, nil
+	}
+
+	header := records[0]
+	rows := make([]any, 0, len(records)-1)
+
+	for _, record := range records[1:] {
+		row := make(map[string]any, len(header))
+		for idx, column := range header {
+			row[column] = record[idx]
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// formatOf returns the format of the file at path based on its extension.
+// Files with an unknown extension are treated as text.
+func formatOf(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return JsonFormat
+	case ".yaml", ".yml":
+		return YamlFormat
+	case ".csv":
+		return CsvFormat
+	}
+
+	return TextFormat
+}
@@ -0,0 +1,239 @@
+package workflow
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/santhanuv/srotas/internal/log"
+	"github.com/santhanuv/srotas/internal/store"
+)
+
+func TestWriteFile_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		write WriteFile
+		err   bool
+	}{
+		{
+			name:  "'name' is not provided",
+			write: WriteFile{Type: "write_file", Path: "out.json", Value: "order"},
+			err:   true,
+		},
+		{
+			name:  "'path' is not provided",
+			write: WriteFile{Type: "write_file", StepName: "name", Value: "order"},
+			err:   true,
+		},
+		{
+			name:  "'value' is not provided",
+			write: WriteFile{Type: "write_file", StepName: "name", Path: "out.json"},
+			err:   true,
+		},
+		{
+			name:  "csv format is not supported",
+			write: WriteFile{Type: "write_file", StepName: "name", Path: "out.csv", Value: "order", Format: CsvFormat},
+			err:   true,
+		},
+		{
+			name:  "csv extension is written as text",
+			write: WriteFile{Type: "write_file", StepName: "name", Path: "out.csv", Value: "order"},
+			err:   false,
+		},
+		{
+			name:  "All required fields are provided",
+			write: WriteFile{Type: "write_file", StepName: "name", Path: "out.json", Value: "order"},
+			err:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		write := tt.write
+		err := tt.write.Validate()
+
+		if !reflect.DeepEqual(tt.write, write) {
+			t.Errorf("in test %q; expected the step not to be modified but got %+v", tt.name, tt.write)
+		}
+
+		if !tt.err && err != nil {
+			t.Errorf("in test %q; expected no error but got %q", tt.name, err)
+			continue
+		}
+
+		if tt.err {
+			if err == nil {
+				t.Errorf("in test %q; expected error but got none", tt.name)
+				continue
+			}
+
+			var target *ValidationError
+			if !errors.As(err, &target) {
+				t.Errorf("in test %q; got %q; want validation error", tt.name, err)
+			}
+		}
+	}
+}
+
+func TestReadFile_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		read ReadFile
+		err  bool
+	}{
+		{
+			name: "'name' is not provided",
+			read: ReadFile{Type: "read_file", Path: "users.csv", As: "users"},
+			err:  true,
+		},
+		{
+			name: "'path' is not provided",
+			read: ReadFile{Type: "read_file", StepName: "name", As: "users"},
+			err:  true,
+		},
+		{
+			name: "'as' is not provided",
+			read: ReadFile{Type: "read_file", StepName: "name", Path: "users.csv"},
+			err:  true,
+		},
+		{
+			name: "invalid format",
+			read: ReadFile{Type: "read_file", StepName: "name", Path: "users.csv", As: "users", Format: "xml"},
+			err:  true,
+		},
+		{
+			name: "All required fields are provided",
+			read: ReadFile{Type: "read_file", StepName: "name", Path: "users.csv", As: "users"},
+			err:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		err := tt.read.Validate()
+
+		if !tt.err && err != nil {
+			t.Errorf("in test %q; expected no error but got %q", tt.name, err)
+			continue
+		}
+
+		if tt.err {
+			if err == nil {
+				t.Errorf("in test %q; expected error but got none", tt.name)
+				continue
+			}
+
+			var target *ValidationError
+			if !errors.As(err, &target) {
+				t.Errorf("in test %q; got %q; want validation error", tt.name, err)
+			}
+		}
+	}
+}
+
+func TestWriteFile_ReadFile_Execute(t *testing.T) {
+	dir := t.TempDir()
+
+	configPath := writeConfig(t, dir, "main.yaml", `
+steps:
+  - type: write_file
+    step:
+      name: dump json
+      path: artifacts/order.json
+      value: "order"
+      pretty: true
+  - type: write_file
+    step:
+      name: dump yaml
+      path: artifacts/order.yaml
+      value: "order"
+  - type: write_file
+    step:
+      name: dump text
+      path: artifacts/order.txt
+      value: "'order ' + string(order.id)"
+  - type: write_file
+    step:
+      name: dump csv
+      path: artifacts/order.csv
+      value: "'id,item\\n' + string(order.id) + ',' + order.items[0] + '\\n'"
+  - type: read_file
+    step:
+      name: load json
+      path: artifacts/order.json
+      as: fromJson
+  - type: read_file
+    step:
+      name: load yaml
+      path: artifacts/order.yaml
+      as: fromYaml
+  - type: read_file
+    step:
+      name: load text
+      path: artifacts/order.txt
+      as: fromText
+  - type: read_file
+    step:
+      name: load csv
+      path: users.csv
+      as: users
+  - type: read_file
+    step:
+      name: load written csv
+      path: artifacts/order.csv
+      as: fromCsv
+`)
+	writeConfig(t, dir, "users.csv", "id,email\n1,a@example.com\n2,b@example.com\n")
+
+	logBuf := bytes.NewBuffer(nil)
+	logger := log.New(logBuf, logBuf, logBuf)
+
+	def, err := ParseConfig(configPath, logger)
+	if err != nil {
+		t.Fatalf("failed to setup test: %v", err)
+	}
+
+	s := store.NewStore(map[string]any{"order": map[string]any{"id": 7, "items": []any{"book"}}})
+
+	context, err := NewExecutionContext(WithStore(s), WithLogger(logger))
+	if err != nil {
+		t.Fatalf("failed to setup test: unable to create execution context")
+	}
+
+	if err := Execute(def, context); err != nil {
+		t.Fatalf("expected no error but got %q", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "artifacts", "order.json"))
+	if err != nil {
+		t.Fatalf("expected file relative to the config but got %v", err)
+	}
+
+	if want := "{\n  \"id\": 7,\n  \"items\": [\n    \"book\"\n  ]\n}\n"; string(data) != want {
+		t.Errorf("got %q; want %q", data, want)
+	}
+
+	tests := []struct {
+		variable string
+		want     any
+	}{
+		{variable: "fromJson", want: map[string]any{"id": float64(7), "items": []any{"book"}}},
+		{variable: "fromYaml", want: map[string]any{"id": 7, "items": []any{"book"}}},
+		{variable: "fromText", want: "order 7"},
+		{
+			variable: "users",
+			want: []any{
+				map[string]any{"id": "1", "email": "a@example.com"},
+				map[string]any{"id": "2", "email": "b@example.com"},
+			},
+		},
+		{variable: "fromCsv", want: []any{map[string]any{"id": "7", "item": "book"}}},
+	}
+
+	for _, tt := range tests {
+		if got, _ := s.Get(tt.variable); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("got %#v for '%s'; want %#v", got, tt.variable, tt.want)
+		}
+	}
+}
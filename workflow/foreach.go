@@ -1,20 +1,42 @@
 package workflow
 
 import (
+	"errors"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
 
 	"github.com/expr-lang/expr"
+	"github.com/santhanuv/srotas/internal/store"
 )
 
 // ForEach represents a loop step that executes the steps in Body for each item in List.
 // The As field defines the variable name that stores each item during execution.
+// List may evaluate to a list or a map; the entries of a map are iterated in the order of their keys.
+// If Concurrency is greater than 1, up to Concurrency items are processed at the same time, and the loop fails
+// if more than one item modifies the same variable.
+// The loop fails without processing any item if the list has more items than MaxIterations. If MaxIterations is not provided,
+// the loop fails once it starts more items than the default maximum of the execution. No new items are started once MaxDuration is exceeded.
 type ForEach struct {
-	Type     string   // The type of the step.
-	StepName string   `yaml:"name"` // Identifier for the step.
-	List     string   // The list of items to iterate over.
-	As       string   // The variable name to store the current item in each iteration.
-	Body     StepList // The sequence of steps executed for each item.
+	Type          string   // The type of the step.
+	StepName      string   `yaml:"name"` // Identifier for the step.
+	List          string   // The list or map of items to iterate over.
+	As            string   // The variable name to store the current item, or the value of the current entry of a map.
+	Index         string   // The variable name to store the position of the current item, if provided.
+	Key           string   // The variable name to store the key of the current entry of a map, if provided.
+	Concurrency   uint     // Maximum number of items processed at the same time.
+	MaxIterations uint     `yaml:"max_iterations"` // Maximum number of items.
+	MaxDuration   uint     `yaml:"max_duration"`   // Maximum time (milliseconds) allowed for the loop.
+	Body          StepList // The sequence of steps executed for each item.
+}
+
+// forEachItem represents an item of the list, or an entry of the map, of a [ForEach] step.
+type forEachItem struct {
+	key   any // Key of the entry, nil for items of a list.
+	value any // The item, or the value of the entry.
 }
 
 // Validate checks the fields of the [ForEach] step and returns a list of validation errors, if any.
@@ -37,6 +59,19 @@ func (f *ForEach) Validate() error {
 		vErr.Add(RequiredFieldError{Field: "body"})
 	}
 
+	names := []string{f.As}
+	for _, name := range []string{f.Index, f.Key} {
+		if name == "" {
+			continue
+		}
+
+		if slices.Contains(names, name) {
+			vErr.Add(fmt.Errorf("variable '%s' is used more than once in as, index and key", name))
+		}
+
+		names = append(names, name)
+	}
+
 	if vErr.HasError() {
 		return fmt.Errorf("foreach step: %w", &vErr)
 	}
@@ -48,42 +83,276 @@ func (f *ForEach) Name() string {
 	return f.StepName
 }
 
+func (f *ForEach) stepLists() []StepList {
+	return []StepList{f.Body}
+}
+
 // Execute executes the step with the specified context.
+// Items processed concurrently are executed against isolated copies of the store, and the variables
+// they set or remove are merged back in the order of the items once all of them complete.
 func (f *ForEach) Execute(context *ExecutionContext) error {
-	variables := context.store.Map()
+	variables := context.variables()
 
-	if val, ok := variables[f.As]; val != nil && ok {
-		return fmt.Errorf("foreach step '%s': variable '%s' already defined.", f.StepName, f.As)
+	for _, name := range f.variables() {
+		if val, ok := variables[name]; val != nil && ok {
+			return fmt.Errorf("foreach step '%s': variable '%s' already defined.", f.StepName, name)
+		}
 	}
 
-	defer context.store.Remove(f.As)
-
-	program, err := expr.Compile(f.List, expr.Env(variables), expr.AsKind(reflect.Slice))
+	output, err := expr.Eval(f.List, variables)
 
 	if err != nil {
 		return err
 	}
 
-	output, err := expr.Run(program, variables)
+	items, err := forEachItems(output)
+
+	if err != nil {
+		return fmt.Errorf("foreach step '%s': %v", f.StepName, err)
+	}
+
+	limit := newLoopLimit(context, f.StepName, f.MaxIterations, f.MaxDuration)
+
+	// The global limit is checked as each item starts, since it guards against runaway loops rather than long lists.
+	if f.MaxIterations != 0 && uint(len(items)) > f.MaxIterations {
+		return fmt.Errorf("foreach step '%s': list has %d items, more than max_iterations of %d", f.StepName, len(items), f.MaxIterations)
+	}
+
+	if f.Concurrency > 1 {
+		err = f.executeConcurrently(context, items, limit)
+	} else {
+		err = f.executeSerially(context, items, limit)
+	}
 
 	if err != nil {
 		return err
 	}
 
-	items := output.([]any)
+	context.logger.Debug("successfully executed foreach step '%s'", f.StepName)
+	return nil
+}
+
+// executeSerially executes the body for each item one at a time, stopping at the first failure.
+func (f *ForEach) executeSerially(context *ExecutionContext, items []forEachItem, limit *loopLimit) error {
+	defer func() {
+		for _, name := range f.variables() {
+			context.store.Remove(name)
+		}
+	}()
 
-	for _, item := range items {
-		context.store.Set(f.As, item)
+	for idx, item := range items {
+		if err := limit.next(f.itemVariables(idx, item)); err != nil {
+			return err
+		}
 
-		for _, step := range f.Body {
-			err := step.Execute(context)
+		f.setItem(context.store, idx, item)
 
-			if err != nil {
-				return err
+		stop, err := executeLoopBody(f.Body, context)
+		if err != nil {
+			return fmt.Errorf("foreach step '%s': %s: %w", f.StepName, item.describe(idx), err)
+		}
+
+		if stop {
+			break
+		}
+	}
+
+	return nil
+}
+
+// executeConcurrently executes the body for up to f.Concurrency items at the same time.
+// All items are processed even if some of them fail, and the failed items are reported together.
+// A break, or exceeding the maximum duration, stops new items from being started, while the items in progress are completed.
+func (f *ForEach) executeConcurrently(context *ExecutionContext, items []forEachItem, limit *loopLimit) error {
+	base := context.store.Map()
+	results := make([]branchResult, len(items))
+	sem := make(chan struct{}, f.Concurrency)
+	started := len(items)
+
+	var (
+		wg       sync.WaitGroup
+		stopped  atomic.Bool
+		limitErr error
+	)
+
+	context.logger.Info("processing %d items of foreach step '%s' with concurrency %d", len(items), f.StepName, f.Concurrency)
+
+	for idx, item := range items {
+		sem <- struct{}{}
+
+		if stopped.Load() || context.Interrupted() != nil {
+			<-sem
+			started = idx
+			break
+		}
+
+		if limitErr = limit.next(f.itemVariables(idx, item)); limitErr != nil {
+			<-sem
+			started = idx
+			break
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			// Each item gets a deep copy, so that maps and lists are not shared between items.
+			iStore := context.store.Clone()
+			f.setItem(iStore, idx, item)
+
+			iContext := context.fork(iStore, context.logger.WithTag(fmt.Sprintf("%s %s", f.StepName, item.describe(idx))))
+
+			stop, err := executeLoopBody(f.Body, iContext)
+			if stop {
+				stopped.Store(true)
 			}
+
+			set, removed := diffVariables(base, iStore.Map())
+			for _, name := range f.variables() {
+				delete(set, name)
+			}
+
+			results[idx] = branchResult{
+				set:     set,
+				removed: removed,
+				err:     err,
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	var errs []error
+
+	owners := map[string]int{}
+
+	// conflict reports a variable modified by more than one item, which keeps the value of the first item.
+	conflict := func(name string, idx int) bool {
+		owner, ok := owners[name]
+		if ok {
+			errs = append(errs, fmt.Errorf("%s: variable '%s' is also modified by %s", items[idx].describe(idx), name, items[owner].describe(owner)))
+			return true
 		}
+
+		owners[name] = idx
+
+		return false
+	}
+
+	for idx, result := range results[:started] {
+		if result.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", items[idx].describe(idx), result.err))
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(result.set)) {
+			if !conflict(name, idx) {
+				context.store.Set(name, result.set[name])
+			}
+		}
+
+		for _, name := range result.removed {
+			if !conflict(name, idx) {
+				context.store.Remove(name)
+			}
+		}
+	}
+
+	if reason := context.Interrupted(); reason != nil && started < len(items) {
+		errs = append(errs, fmt.Errorf("%w before %s", reason, items[started].describe(started)))
+	} else if limitErr != nil {
+		errs = append(errs, limitErr)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("foreach step '%s': %d of %d item(s) failed:\n%w", f.StepName, len(errs), len(items), errors.Join(errs...))
 	}
 
-	context.logger.Debug("successfully executed foreach step '%s'", f.StepName)
 	return nil
 }
+
+// variables returns the names of the variables set by the step for each item.
+func (f *ForEach) variables() []string {
+	names := []string{f.As}
+
+	if f.Index != "" {
+		names = append(names, f.Index)
+	}
+
+	if f.Key != "" {
+		names = append(names, f.Key)
+	}
+
+	return names
+}
+
+// setItem sets the variables of the item at position idx in s.
+func (f *ForEach) setItem(s *store.Store, idx int, item forEachItem) {
+	for name, val := range f.itemVariables(idx, item) {
+		s.Set(name, val)
+	}
+}
+
+// itemVariables returns the values of the variables set by the step for the item at position idx.
+func (f *ForEach) itemVariables(idx int, item forEachItem) map[string]any {
+	variables := map[string]any{f.As: item.value}
+
+	if f.Index != "" {
+		variables[f.Index] = idx
+	}
+
+	if f.Key != "" {
+		variables[f.Key] = item.key
+	}
+
+	return variables
+}
+
+// describe returns a description of the item at position idx, used in errors and logs.
+func (i forEachItem) describe(idx int) string {
+	if i.key != nil {
+		return fmt.Sprintf("key '%v'", i.key)
+	}
+
+	return fmt.Sprintf("item %d", idx)
+}
+
+// forEachItems returns the items of a list of any type, or the entries of a map sorted by their keys.
+func forEachItems(list any) ([]forEachItem, error) {
+	rv := reflect.ValueOf(list)
+
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		items := make([]forEachItem, 0, rv.Len())
+		for i := range rv.Len() {
+			items = append(items, forEachItem{value: rv.Index(i).Interface()})
+		}
+
+		return items, nil
+	case reflect.Map:
+		items := make([]forEachItem, 0, rv.Len())
+		for _, key := range rv.MapKeys() {
+			items = append(items, forEachItem{key: key.Interface(), value: rv.MapIndex(key).Interface()})
+		}
+
+		slices.SortFunc(items, func(a, b forEachItem) int {
+			ak, bk := fmt.Sprint(a.key), fmt.Sprint(b.key)
+
+			switch {
+			case ak < bk:
+				return -1
+			case ak > bk:
+				return 1
+			}
+
+			return 0
+		})
+
+		return items, nil
+	}
+
+	return nil, fmt.Errorf("list should evaluate to a list or a map, got %T", list)
+}
@@ -1,8 +1,18 @@
 package workflow
 
 import (
+	"bytes"
 	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+
+	"github.com/santhanuv/srotas/internal/log"
+	"github.com/santhanuv/srotas/internal/store"
 )
 
 func TestForEach_Validate(t *testing.T) {
@@ -51,6 +61,18 @@ func TestForEach_Validate(t *testing.T) {
 			},
 			err: true,
 		},
+		{
+			name: "'index' is the same as 'as'",
+			forEach: ForEach{
+				Type:     "forEach",
+				StepName: "name",
+				As:       "as",
+				Index:    "as",
+				List:     "list",
+				Body:     StepList{},
+			},
+			err: true,
+		},
 		{
 			name: "All required fields are provided",
 			forEach: ForEach{
@@ -85,3 +107,233 @@ func TestForEach_Validate(t *testing.T) {
 		}
 	}
 }
+
+func TestForEach_Execute(t *testing.T) {
+	itemErr := errors.New("item failed")
+
+	tests := []struct {
+		name    string
+		forEach ForEach
+		want    map[string]any
+		err     []string
+	}{
+		{
+			name: "typed list with index",
+			forEach: ForEach{
+				List:  "1..3",
+				As:    "item",
+				Index: "idx",
+				Body: StepList{
+					&Set{Variables: map[string]string{"seen": "concat(seen, [idx * 10 + item])"}},
+				},
+			},
+			want: map[string]any{"seen": []any{1, 12, 23}},
+		},
+		{
+			name: "map entries in the order of keys",
+			forEach: ForEach{
+				List:  "{b: 2, a: 1, c: 3}",
+				As:    "value",
+				Key:   "key",
+				Index: "idx",
+				Body: StepList{
+					&Set{Variables: map[string]string{"seen": "concat(seen, [key + string(value) + string(idx)])"}},
+				},
+			},
+			want: map[string]any{"seen": []any{"a10", "b21", "c32"}},
+		},
+		{
+			name: "concurrent items are merged",
+			forEach: ForEach{
+				List:        "[1, 2, 3, 4]",
+				As:          "item",
+				Concurrency: 2,
+				Body: StepList{
+					&guardedStep{Step: &Set{Variables: map[string]string{"second": "item"}}, stepType: "set", when: "item == 2"},
+					&guardedStep{Step: &Set{Variables: map[string]string{"last": "item"}}, stepType: "set", when: "item == 4"},
+				},
+			},
+			want: map[string]any{"second": 2, "last": 4},
+		},
+		{
+			name: "concurrent items modifying the same variable",
+			forEach: ForEach{
+				List:        "[1, 2, 3]",
+				As:          "item",
+				Concurrency: 2,
+				Body: StepList{
+					&Set{Variables: map[string]string{"last": "item"}},
+				},
+			},
+			want: map[string]any{"last": 1},
+			err: []string{
+				"2 of 3 item(s) failed",
+				"item 1: variable 'last' is also modified by item 0",
+				"item 2: variable 'last' is also modified by item 0",
+			},
+		},
+		{
+			name: "concurrent failures are reported",
+			forEach: ForEach{
+				List:        "[1, 2, 3, 4]",
+				As:          "item",
+				Concurrency: 3,
+				Body: StepList{
+					&guardedStep{Step: &failStep{name: "fail", err: itemErr}, stepType: "fail", when: "item % 2 == 0"},
+					&guardedStep{Step: &Set{Variables: map[string]string{"last": "item"}}, stepType: "set", when: "item == 3"},
+				},
+			},
+			want: map[string]any{"last": 3},
+			err:  []string{"2 of 4 item(s) failed", "item 1: item failed", "item 3: item failed"},
+		},
+		{
+			name: "serial failure reports the item",
+			forEach: ForEach{
+				List: "{x: 1}",
+				As:   "value",
+				Body: StepList{&failStep{name: "fail", err: itemErr}},
+			},
+			err: []string{"key 'x': item failed"},
+		},
+		{
+			name: "list has more items than max_iterations",
+			forEach: ForEach{
+				List:          "1..5",
+				As:            "item",
+				MaxIterations: 3,
+				Body:          StepList{&Set{Variables: map[string]string{"last": "item"}}},
+			},
+			want: map[string]any{"last": nil},
+			err:  []string{"list has 5 items, more than max_iterations of 3"},
+		},
+		{
+			name: "list is not a list or map",
+			forEach: ForEach{
+				List: "'items'",
+				As:   "item",
+				Body: StepList{},
+			},
+			err: []string{"list should evaluate to a list or a map"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt.forEach.Type = "forEach"
+		tt.forEach.StepName = "loop"
+
+		s := store.NewStore(map[string]any{"seen": []any{}})
+		logBuf := bytes.NewBuffer(nil)
+
+		context, err := NewExecutionContext(WithStore(s), WithLogger(log.New(logBuf, logBuf, logBuf)))
+		if err != nil {
+			t.Fatalf("failed to setup test: unable to create execution context")
+		}
+
+		err = tt.forEach.Execute(context)
+
+		if len(tt.err) == 0 && err != nil {
+			t.Errorf("in test %q; expected no error but got %q", tt.name, err)
+			continue
+		}
+
+		if len(tt.err) > 0 && err == nil {
+			t.Errorf("in test %q; expected error but got none", tt.name)
+			continue
+		}
+
+		for _, e := range tt.err {
+			if !strings.Contains(err.Error(), e) {
+				t.Errorf("in test %q; expected error to contain %q but got %q", tt.name, e, err)
+			}
+		}
+
+		for name, want := range tt.want {
+			if got, _ := s.Get(name); !reflect.DeepEqual(got, want) {
+				t.Errorf("in test %q; expected '%s' to be %v but got %v", tt.name, name, want, got)
+			}
+		}
+
+		for _, name := range []string{"item", "value", "key", "idx"} {
+			if _, ok := s.Get(name); ok {
+				t.Errorf("in test %q; expected variable '%s' to be removed after the loop", tt.name, name)
+			}
+		}
+	}
+}
+
+func TestForEach_ExecuteConcurrentRequests(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		paths []string
+	)
+
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+
+		w.Write([]byte(`{"ok": true}`))
+	}))
+	defer server.Close()
+
+	forEach := ForEach{
+		Type:        "forEach",
+		StepName:    "fetch users",
+		List:        "[1, 2, 3, 4, 5]",
+		As:          "id",
+		Concurrency: 3,
+		Body: StepList{
+			&Request{Type: "http", StepName: "fetch user", Url: "/users/:id", Method: "GET"},
+		},
+	}
+
+	logBuf := bytes.NewBuffer(nil)
+
+	context, err := NewExecutionContext(WithGlobalOptions(server.URL, nil), WithLogger(log.New(logBuf, logBuf, logBuf)))
+	if err != nil {
+		t.Fatalf("failed to setup test: unable to create execution context")
+	}
+
+	if err := forEach.Execute(context); err != nil {
+		t.Fatalf("expected no error but got %q", err)
+	}
+
+	slices.Sort(paths)
+
+	want := []string{"/users/1", "/users/2", "/users/3", "/users/4", "/users/5"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("expected requests to %v but got %v", want, paths)
+	}
+}
+
+func TestForEach_ExecuteGlobalMaxIterations(t *testing.T) {
+	forEach := ForEach{
+		Type:     "forEach",
+		StepName: "loop",
+		List:     "1..5",
+		As:       "item",
+		Body: StepList{
+			&Set{Variables: map[string]string{"seen": "concat(seen, [item])"}},
+		},
+	}
+
+	s := store.NewStore(map[string]any{"seen": []any{}})
+	logBuf := bytes.NewBuffer(nil)
+
+	context, err := NewExecutionContext(WithStore(s), WithMaxIterations(3), WithLogger(log.New(logBuf, logBuf, logBuf)))
+	if err != nil {
+		t.Fatalf("failed to setup test: unable to create execution context")
+	}
+
+	err = forEach.Execute(context)
+
+	var limitErr *LoopLimitError
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("expected loop limit error but got %v", err)
+	}
+
+	want := []any{1, 2, 3}
+	if seen, _ := s.Get("seen"); !reflect.DeepEqual(seen, want) {
+		t.Errorf("expected 'seen' to be %v but got %v", want, seen)
+	}
+}
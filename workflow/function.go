@@ -0,0 +1,219 @@
+package workflow
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/expr-lang/expr"
+)
+
+// Function represents a named sequence of steps defined once in the configuration and invoked by [Call] steps.
+type Function struct {
+	Params  []string          // Names of the arguments available as variables while executing Steps.
+	Steps   StepList          // Steps executed when the function is called.
+	Returns map[string]string // Results mapped to expressions evaluated after executing Steps.
+}
+
+// Validate checks the fields of the [Function] and returns a list of validation errors, if any.
+func (f *Function) Validate() error {
+	vErr := ValidationError{}
+
+	if f.Steps == nil {
+		vErr.Add(RequiredFieldError{Field: "steps"})
+	}
+
+	for idx, param := range f.Params {
+		if param == "" {
+			vErr.Add(fmt.Errorf("param %d should not be empty", idx))
+		} else if slices.Index(f.Params, param) != idx {
+			vErr.Add(fmt.Errorf("param '%s' is defined more than once", param))
+		}
+	}
+
+	if vErr.HasError() {
+		return &vErr
+	}
+
+	return nil
+}
+
+// checkFunctionCycles returns an error if a function calls itself, directly or through other functions,
+// since such a call would never complete.
+func checkFunctionCycles(functions map[string]*Function) error {
+	calls := make(map[string][]string, len(functions))
+
+	for name, function := range functions {
+		walkSteps(function.Steps, func(step Step) error {
+			if call, ok := step.(*Call); ok {
+				calls[name] = append(calls[name], call.Function)
+			}
+
+			return nil
+		})
+	}
+
+	checked := map[string]bool{}
+
+	var visit func(name string, calling []string) error
+	visit = func(name string, calling []string) error {
+		if idx := slices.Index(calling, name); idx != -1 {
+			cycle := append(slices.Clone(calling[idx:]), name)
+			return fmt.Errorf("function cycle detected: %s", strings.Join(cycle, " -> "))
+		}
+
+		if checked[name] {
+			return nil
+		}
+
+		calling = append(calling, name)
+
+		for _, called := range calls[name] {
+			if err := visit(called, calling); err != nil {
+				return err
+			}
+		}
+
+		checked[name] = true
+
+		return nil
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(functions)) {
+		if err := visit(name, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Call represents a step that executes a [Function] with the given arguments.
+// The values returned by the function are available as the result variable while evaluating Store.
+type Call struct {
+	Type     string            // The type of the step.
+	StepName string            `yaml:"name"` // Identifier for the step.
+	Function string            // Name of the function to call.
+	Args     map[string]string // Function params mapped to expressions evaluated before the call.
+	Store    map[string]string // Variables mapped to expressions evaluated using the result.
+	function *Function         // Function resolved while parsing the configuration.
+}
+
+// Validate checks the fields of the [Call] step and returns a list of validation errors, if any.
+func (c *Call) Validate() error {
+	vErr := ValidationError{}
+
+	if c.StepName == "" {
+		vErr.Add(RequiredFieldError{Field: "name"})
+	}
+
+	if c.Function == "" {
+		vErr.Add(RequiredFieldError{Field: "function"})
+	}
+
+	if vErr.HasError() {
+		return fmt.Errorf("call step: %w", &vErr)
+	}
+
+	return nil
+}
+
+func (c *Call) Name() string {
+	return c.StepName
+}
+
+// bind resolves the called function from functions and checks that the arguments match its params.
+func (c *Call) bind(functions map[string]*Function) error {
+	function, ok := functions[c.Function]
+	if !ok {
+		return fmt.Errorf("call step '%s': function '%s' is not defined", c.StepName, c.Function)
+	}
+
+	for _, param := range function.Params {
+		if _, ok := c.Args[param]; !ok {
+			return fmt.Errorf("call step '%s': missing argument '%s' for function '%s'", c.StepName, param, c.Function)
+		}
+	}
+
+	for arg := range c.Args {
+		if !slices.Contains(function.Params, arg) {
+			return fmt.Errorf("call step '%s': function '%s' has no param '%s'", c.StepName, c.Function, arg)
+		}
+	}
+
+	c.function = function
+
+	return nil
+}
+
+// Execute executes the step with the specified context.
+// The function runs against a copy of the store holding the arguments, so variables stored by its steps
+// are discarded once it completes. Only the values returned by the function are available to Store.
+func (c *Call) Execute(context *ExecutionContext) error {
+	function := c.function
+	if function == nil {
+		return fmt.Errorf("call step '%s': function '%s' is not defined", c.StepName, c.Function)
+	}
+
+	vars := context.variables()
+	args := make(map[string]any, len(c.Args))
+
+	for name, ae := range c.Args {
+		val, err := expr.Eval(ae, vars)
+		if err != nil {
+			return fmt.Errorf("call step '%s': invalid expression '%s' for argument '%s': %v", c.StepName, ae, name, err)
+		}
+
+		args[name] = val
+	}
+
+	context.logger.Info("calling function '%s' for step '%s'", c.Function, c.StepName)
+
+	fStore := context.store.Clone()
+	fStore.Add(args)
+
+	result, err := function.run(context.fork(fStore, context.logger))
+	if err != nil {
+		return fmt.Errorf("call step '%s': function '%s': %w", c.StepName, c.Function, err)
+	}
+
+	vars["result"] = result
+	newVars := make(map[string]any, len(c.Store))
+
+	for vn, ve := range c.Store {
+		val, err := expr.Eval(ve, vars)
+		if err != nil {
+			return fmt.Errorf("call step '%s': invalid expression '%s' for variable '%s': %v", c.StepName, ve, vn, err)
+		}
+
+		newVars[vn] = val
+	}
+
+	context.store.Add(newVars)
+
+	context.logger.Debug("successfully executed call step '%s'", c.StepName)
+
+	return nil
+}
+
+// run executes the steps of the function and returns the evaluated results.
+func (f *Function) run(context *ExecutionContext) (map[string]any, error) {
+	if err := executeSteps(f.Steps, context); err != nil {
+		return nil, err
+	}
+
+	vars := context.variables()
+	result := make(map[string]any, len(f.Returns))
+
+	for name, re := range f.Returns {
+		val, err := expr.Eval(re, vars)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression '%s' for result '%s': %v", re, name, err)
+		}
+
+		result[name] = val
+	}
+
+	return result, nil
+}
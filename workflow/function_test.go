@@ -0,0 +1,286 @@
+package workflow
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/santhanuv/srotas/internal/log"
+	"github.com/santhanuv/srotas/internal/store"
+)
+
+func TestCall_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		call Call
+		err  bool
+	}{
+		{
+			name: "'name' is not provided",
+			call: Call{
+				Type:     "call",
+				Function: "function",
+			},
+			err: true,
+		},
+		{
+			name: "'function' field is not provided",
+			call: Call{
+				Type:     "call",
+				StepName: "name",
+			},
+			err: true,
+		},
+		{
+			name: "All required fields are provided",
+			call: Call{
+				Type:     "call",
+				StepName: "name",
+				Function: "function",
+			},
+			err: false,
+		},
+	}
+
+	for _, tt := range tests {
+		err := tt.call.Validate()
+
+		if !tt.err && err != nil {
+			t.Errorf("in test %q; expected no error but got %q", tt.name, err)
+			continue
+		}
+
+		if tt.err {
+			if err == nil {
+				t.Errorf("in test %q; expected error but got none", tt.name)
+				continue
+			}
+
+			var target *ValidationError
+			if !errors.As(err, &target) {
+				t.Errorf("in test %q; got %q; want validation error", tt.name, err)
+			}
+		}
+	}
+}
+
+func TestParseConfig_CallBinding(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{
+			name: "function is not defined",
+			config: `
+steps:
+  - type: call
+    step:
+      name: create
+      function: create_order
+`,
+			err: "function 'create_order' is not defined",
+		},
+		{
+			name: "argument is missing",
+			config: `
+functions:
+  create_order:
+    params: [sku, qty]
+    steps: []
+steps:
+  - type: forEach
+    step:
+      name: orders
+      list: "[1, 2]"
+      as: item
+      body:
+        - type: call
+          step:
+            name: create
+            function: create_order
+            args:
+              sku: "'A-1'"
+`,
+			err: "missing argument 'qty'",
+		},
+		{
+			name: "unknown argument",
+			config: `
+functions:
+  create_order:
+    params: [sku]
+    steps: []
+steps:
+  - type: call
+    step:
+      name: create
+      function: create_order
+      args:
+        sku: "'A-1'"
+        qty: "1"
+`,
+			err: "has no param 'qty'",
+		},
+		{
+			name: "function calls itself",
+			config: `
+functions:
+  loop:
+    steps:
+      - type: call
+        step:
+          name: again
+          function: loop
+steps:
+  - type: call
+    step:
+      name: start
+      function: loop
+`,
+			err: "function cycle detected: loop -> loop",
+		},
+		{
+			name: "functions call each other within nested steps",
+			config: `
+functions:
+  first:
+    steps:
+      - type: if
+        step:
+          name: maybe
+          condition: "true"
+          then:
+            - type: call
+              step:
+                name: second
+                function: second
+  second:
+    steps:
+      - type: call
+        step:
+          name: first
+          function: first
+          when: "true"
+steps: []
+`,
+			err: "function cycle detected: first -> second -> first",
+		},
+	}
+
+	for _, tt := range tests {
+		dir := t.TempDir()
+		path := writeConfig(t, dir, "config.yaml", tt.config)
+
+		logBuf := bytes.NewBuffer(nil)
+		_, err := ParseConfig(filepath.Join(path), log.New(logBuf, logBuf, logBuf))
+
+		if err == nil {
+			t.Errorf("in test %q; expected error but got none", tt.name)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("in test %q; expected error containing %q but got %q", tt.name, tt.err, err)
+		}
+	}
+}
+
+func TestCall_Execute(t *testing.T) {
+	function := &Function{
+		Params: []string{"qty"},
+		Steps: StepList{
+			&storeStep{vars: map[string]any{"order": map[string]any{"id": 7}}},
+		},
+		Returns: map[string]string{"order_id": "order.id * qty"},
+	}
+
+	call := Call{
+		Type:     "call",
+		StepName: "create order",
+		Function: "create_order",
+		Args:     map[string]string{"qty": "2"},
+		Store:    map[string]string{"first_order": "result.order_id"},
+	}
+
+	if err := call.bind(map[string]*Function{"create_order": function}); err != nil {
+		t.Fatalf("expected no error but got %q", err)
+	}
+
+	s := store.NewStore(nil)
+	logBuf := bytes.NewBuffer(nil)
+
+	context, err := NewExecutionContext(WithStore(s), WithLogger(log.New(logBuf, logBuf, logBuf)))
+	if err != nil {
+		t.Fatalf("failed to setup test: unable to create execution context")
+	}
+
+	if err := call.Execute(context); err != nil {
+		t.Fatalf("expected no error but got %q\nLogs: %s", err, logBuf.String())
+	}
+
+	if orderID, _ := s.Get("first_order"); orderID != 14 {
+		t.Errorf("expected 'first_order' to be 14 but got '%v'", orderID)
+	}
+
+	if _, ok := s.Get("qty"); ok {
+		t.Errorf("expected argument 'qty' to be removed after the call")
+	}
+
+	if _, ok := s.Get("order"); ok {
+		t.Errorf("expected variable 'order' stored by the function to be discarded after the call")
+	}
+}
+
+func TestCall_ExecuteKeepsCallerVariables(t *testing.T) {
+	function := &Function{
+		Params: []string{"qty"},
+		Steps: StepList{
+			&storeStep{vars: map[string]any{"total": 3}},
+			&mutateStep{variable: "order", key: "status", val: "changed"},
+		},
+		Returns: map[string]string{"total": "total * qty * order.id"},
+	}
+
+	call := Call{
+		Type:     "call",
+		StepName: "update order",
+		Function: "update_order",
+		Args:     map[string]string{"qty": "2"},
+		Store:    map[string]string{"order_total": "result.total"},
+	}
+
+	if err := call.bind(map[string]*Function{"update_order": function}); err != nil {
+		t.Fatalf("expected no error but got %q", err)
+	}
+
+	s := store.NewStore(map[string]any{
+		"qty":   5,
+		"total": 1,
+		"order": map[string]any{"id": 4, "status": "created"},
+	})
+	logBuf := bytes.NewBuffer(nil)
+
+	context, err := NewExecutionContext(WithStore(s), WithLogger(log.New(logBuf, logBuf, logBuf)))
+	if err != nil {
+		t.Fatalf("failed to setup test: unable to create execution context")
+	}
+
+	if err := call.Execute(context); err != nil {
+		t.Fatalf("expected no error but got %q\nLogs: %s", err, logBuf.String())
+	}
+
+	expected := map[string]any{
+		"qty":         5,
+		"total":       1,
+		"order":       map[string]any{"id": 4, "status": "created"},
+		"order_total": 24,
+	}
+
+	if got := s.Map(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected variables to be %v but got %v", expected, got)
+	}
+}
@@ -51,7 +51,7 @@ func (h *Header) UnmarshalYAML(value *yaml.Node) error {
 // h headers are preferred over global headers.
 func (h *Header) compile(context *ExecutionContext) (map[string][]string, error) {
 	gHeaders := context.globalOptions.headers
-	vars := context.store.Map()
+	vars := context.variables()
 
 	if h == nil {
 		cHeaders := make(map[string][]string, len(gHeaders))
@@ -111,7 +111,7 @@ func (q *QueryParam) compile(context *ExecutionContext) (map[string][]string, er
 	}
 
 	cqps := make(map[string][]string, len(*q))
-	vars := context.store.Map()
+	vars := context.variables()
 
 	for key, ves := range *q {
 		vals := make([]string, 0, len(ves))
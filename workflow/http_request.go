@@ -2,14 +2,22 @@ package workflow
 
 import (
 	"bytes"
+	ctx "context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 	"text/template"
+	"text/template/parse"
 	"time"
 
 	"github.com/expr-lang/expr"
 	"github.com/santhanuv/srotas/internal/http"
+	"github.com/tidwall/sjson"
 	"gopkg.in/yaml.v3"
 )
 
@@ -25,6 +33,10 @@ type Request struct {
 	Store       map[string]string // Variables mapped to expressions evaluated using the response.
 	Delay       uint              // Wait time (milliseconds) before executing the request.
 	Validations *Validator        // Validation rules for the response.
+	Retry       *Retry            // Retry policy applied when the request fails.
+	Timeout     uint              // Maximum time (milliseconds) allowed for the request, overriding the config timeout.
+	// Format used to decode the response body, overriding the format detected from the Content-Type of the response.
+	ResponseFormat string `yaml:"response_format"`
 }
 
 // Validate checks the fields of the [Request] step and returns a list of validation errors, if any.
@@ -45,6 +57,14 @@ func (r *Request) Validate() error {
 		vErr.Add(err)
 	}
 
+	if err := r.Retry.Validate(); err != nil {
+		vErr.Add(err)
+	}
+
+	if r.ResponseFormat != "" && !slices.Contains(http.Formats, r.ResponseFormat) {
+		vErr.Add(fmt.Errorf("response_format should be one of %s", strings.Join(http.Formats, ", ")))
+	}
+
 	if vErr.HasError() {
 		return fmt.Errorf("http request step: %w", &vErr)
 	}
@@ -57,6 +77,8 @@ func (r *Request) Name() string {
 }
 
 // Execute executes the step with the specified context.
+// If a retry policy is defined, the request is re-sent until the response passes validation
+// or the maximum number of attempts is reached.
 func (r *Request) Execute(context *ExecutionContext) error {
 	req, err := r.build(context)
 	if err != nil {
@@ -69,58 +91,195 @@ func (r *Request) Execute(context *ExecutionContext) error {
 	delayDuration := time.Duration(r.Delay) * time.Millisecond
 	if delayDuration > 0 {
 		context.logger.Info("Delaying request for %s", delayDuration)
-		time.Sleep(delayDuration)
+
+		if err := context.sleep(delayDuration); err != nil {
+			return fmt.Errorf("http request '%s' aborted during delay: %w", r.StepName, err)
+		}
 	}
 
-	res, err := context.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed executing http request '%s': %w", r.StepName, err)
+	saved := saveResponse(context)
+	var stored map[string]string
+	defer func() { saved.restore(context, stored) }()
+
+	maxAttempts := r.Retry.attempts()
+	outcomes := make([]string, 0, maxAttempts)
+
+	for attempt := uint(1); ; attempt++ {
+		resBody, retry, err := r.attempt(context, req)
+
+		if err == nil {
+			context.logger.Debug("http response validation has been completed successfully.")
+
+			if err := resBody.store(r.Store, context); err != nil {
+				return fmt.Errorf("failed executing http request '%s': %v", r.StepName, err)
+			}
+
+			stored = r.Store
+
+			return nil
+		}
+
+		if r.Retry == nil || context.Interrupted() != nil {
+			return err
+		}
+
+		outcomes = append(outcomes, fmt.Sprintf("attempt %d: %v", attempt, err))
+
+		if !retry || attempt >= maxAttempts {
+			break
+		}
+
+		backoff := r.Retry.backoff(attempt)
+		context.logger.Info("http request '%s' attempt %d/%d failed; retrying in %s", r.StepName, attempt, maxAttempts, backoff)
+		context.logger.Debug("http request '%s' attempt %d failed: %v", r.StepName, attempt, err)
+
+		if err := context.sleep(backoff); err != nil {
+			return fmt.Errorf("http request '%s' aborted while waiting to retry: %w", r.StepName, err)
+		}
 	}
 
-	context.logger.Info("http request '%s' responded with status %d", r.StepName, res.StatusCode)
-	context.logger.DebugJson(res.Body, "http response: ")
+	return fmt.Errorf("http request '%s' failed after %d attempt(s):\n%s", r.StepName, len(outcomes), strings.Join(outcomes, "\n"))
+}
 
-	var pb any
+// attempt sends the request once and validates the response.
+// It returns the response body on success. On failure, it also reports whether the request can be retried.
+func (r *Request) attempt(context *ExecutionContext, req *http.Request) (*responseBody, bool, error) {
+	res, pb, parseErr, err := r.send(context, context.ctx, req)
+	if err != nil {
+		return nil, true, err
+	}
 
-	if res.Body != nil {
-		err = json.Unmarshal(res.Body, &pb)
+	if r.Retry != nil && r.Retry.RetryOn != "" {
+		retry, err := r.Retry.shouldRetry(context, res, pb)
 		if err != nil {
-			return fmt.Errorf("failed to parse response body for Http request %q: %v", r.StepName, err)
+			return nil, false, fmt.Errorf("http request '%s': %v", r.StepName, err)
+		}
+
+		if retry {
+			return nil, true, fmt.Errorf("http request '%s': retry condition '%s' matched the response with status %d", r.StepName, r.Retry.RetryOn, res.StatusCode)
 		}
 	}
 
+	if parseErr != nil {
+		return nil, r.Retry != nil && r.Retry.RetryOn == "", parseErr
+	}
+
 	resBody := responseBody{
 		body: pb,
+		res:  newResponse(res, pb),
 	}
 
-	context.store.Set("response", resBody.body)
-	defer context.store.Remove("response")
+	setResponse(context, &resBody)
 
 	err = r.Validations.Validate(context, res.StatusCode, &resBody)
 	if err != nil {
-		fres := struct {
-			StatusCode uint
-			Body       any
-		}{
-			StatusCode: res.StatusCode,
-			Body:       resBody.body,
-		}
-		jres, je := json.MarshalIndent(fres, "", " ")
+		return nil, r.Retry != nil && r.Retry.RetryOn == "", responseError(r.StepName, resBody.res, err)
+	}
+
+	return &resBody, false, nil
+}
 
-		if je != nil {
-			return fmt.Errorf("http request '%s': unable to output response: %v", r.StepName, je)
+// send sends the request once and decodes the response body in the format of the step or of the response.
+// If the body cannot be decoded, the raw body is returned as a string along with the parse error.
+// A non-nil err is returned only when the request could not be sent.
+func (r *Request) send(context *ExecutionContext, c ctx.Context, req *http.Request) (res *http.Response, body any, parseErr error, err error) {
+	res, err = context.httpClient.Do(c, req)
+
+	var tErr *http.TimeoutError
+	if errors.As(err, &tErr) {
+		limit := "config timeout"
+		if r.Timeout != 0 {
+			limit = "step timeout"
 		}
 
-		return fmt.Errorf("http request '%s': %v\nresponse: %s", r.StepName, err, string(jres))
+		return nil, nil, nil, fmt.Errorf("http request '%s' exceeded the %s of %s: %w", r.StepName, limit, tErr.Timeout, err)
 	}
-	context.logger.Debug("http response validation has been completed successfully.")
 
-	err = resBody.store(r.Store, context)
 	if err != nil {
-		return fmt.Errorf("failed executing http request '%s': %v", r.StepName, err)
+		return nil, nil, nil, fmt.Errorf("failed executing http request '%s': %w", r.StepName, err)
 	}
 
-	return nil
+	context.logger.Info("http request '%s' responded with status %d", r.StepName, res.StatusCode)
+
+	format := r.ResponseFormat
+	if format == "" || format == http.AutoFormat {
+		format = res.Format()
+	}
+
+	switch {
+	case len(res.Body) == 0:
+		context.logger.Debug("http response: empty body")
+	case format == http.JsonFormat:
+		context.logger.DebugJson(res.Body, "http response: ")
+	case format == http.BinaryFormat:
+		context.logger.Debug("http response: %d bytes of binary content", len(res.Body))
+	default:
+		context.logger.Debug("http response: \n%s", res.Body)
+	}
+
+	body, err = res.Decode(format)
+	if err != nil {
+		parseErr = fmt.Errorf("failed to parse response body for Http request %q as %s: %v", r.StepName, format, err)
+		body = string(res.Body)
+		err = nil
+	}
+
+	context.lastResponse = body
+
+	return res, body, parseErr, nil
+}
+
+// setResponse sets the decoded body of the response as the response variable, the raw body as the raw_response variable
+// and the [Response] as the res variable.
+func setResponse(context *ExecutionContext, rb *responseBody) {
+	context.store.Set("response", rb.body)
+	context.store.Set("raw_response", rb.res.Raw)
+	context.store.Set("res", rb.res)
+}
+
+// savedResponse holds the values the variables set by [setResponse] had before a step set them.
+// Variables that were not defined are not included.
+type savedResponse map[string]any
+
+// saveResponse returns the values of the variables set by [setResponse], so that they can be restored after the step.
+func saveResponse(context *ExecutionContext) savedResponse {
+	saved := savedResponse{}
+
+	for _, name := range []string{"response", "raw_response", "res"} {
+		if val, ok := context.store.Get(name); ok {
+			saved[name] = val
+		}
+	}
+
+	return saved
+}
+
+// restore restores the variables set by [setResponse] to their saved values, removing the ones that were not defined.
+// Variables in stored were set by the store block of the step and are kept.
+func (s savedResponse) restore(context *ExecutionContext, stored map[string]string) {
+	for _, name := range []string{"response", "raw_response", "res"} {
+		if _, ok := stored[name]; ok {
+			continue
+		}
+
+		if val, ok := s[name]; ok {
+			context.store.Set(name, val)
+		} else {
+			context.store.Remove(name)
+		}
+	}
+}
+
+// responseError returns an error for the http request step identified by name,
+// including the status code, headers and body of the response that caused the error.
+func responseError(name string, res *Response, err error) error {
+	jres, je := json.MarshalIndent(res, "", " ")
+
+	if je != nil {
+		return fmt.Errorf("http request '%s': unable to output response: %v", name, je)
+	}
+
+	return fmt.Errorf("http request '%s': %v\nresponse: %s", name, err, string(jres))
 }
 
 // build returns a custom http request after evaluating all value expressions.
@@ -134,17 +293,21 @@ func (r *Request) build(context *ExecutionContext) (*http.Request, error) {
 	}
 
 	req := http.Request{
-		Method: r.Method,
-		Url:    eURL,
+		Method:  r.Method,
+		Url:     eURL,
+		Timeout: r.Timeout,
 	}
 
+	var contentType string
+
 	if r.Body != nil {
-		body, err := r.Body.build(context)
+		body, cType, err := r.Body.build(context)
 		if err != nil {
 			return nil, err
 		}
 
 		req.Body = body
+		contentType = cType
 	}
 
 	headers, err := r.Headers.compile(context)
@@ -152,6 +315,17 @@ func (r *Request) build(context *ExecutionContext) (*http.Request, error) {
 		return nil, err
 	}
 
+	// The content type of form and multipart bodies overrides the headers, as it defines the boundary of multipart bodies.
+	if contentType != "" {
+		for name := range headers {
+			if strings.EqualFold(name, "Content-Type") {
+				delete(headers, name)
+			}
+		}
+
+		headers["Content-Type"] = []string{contentType}
+	}
+
 	req.Headers = headers
 
 	queryParams, err := r.QueryParams.compile(context)
@@ -171,6 +345,8 @@ func (r *Request) buildURL(baseUrl string, context *ExecutionContext) (string, e
 	}
 
 	store := context.store
+	eURL := r.Url
+
 	for idx, urlParam := range strings.Split(r.Url, "/:") {
 		if idx == 0 {
 			continue
@@ -181,14 +357,14 @@ func (r *Request) buildURL(baseUrl string, context *ExecutionContext) (string, e
 			return "", fmt.Errorf("variable '%s' not found for url '%s'", urlParam, r.Url)
 		}
 
-		r.Url = strings.ReplaceAll(r.Url, fmt.Sprintf(":%s", urlParam), fmt.Sprintf("%v", val))
+		eURL = strings.ReplaceAll(eURL, fmt.Sprintf(":%s", urlParam), fmt.Sprintf("%v", val))
 	}
 
-	abURL := r.Url
+	abURL := eURL
 
-	if !strings.Contains(r.Url, "://") {
+	if !strings.Contains(eURL, "://") {
 		baseUrl = strings.TrimSuffix(baseUrl, "/")
-		url := strings.TrimPrefix(r.Url, "/")
+		url := strings.TrimPrefix(eURL, "/")
 
 		abURL = fmt.Sprintf("%s/%s", baseUrl, url)
 	}
@@ -197,20 +373,38 @@ func (r *Request) buildURL(baseUrl string, context *ExecutionContext) (string, e
 }
 
 // RequestBody represents the payload for an HTTP request step.
+// The payload is either a template, a JSON body, a form-urlencoded body or a multipart/form-data body.
 //   - Data is a map where keys represent JSON fields to update or add,
 //     and values are expressions evaluated at runtime before being inserted into the Content.
+//     Keys used by the template are only passed to the template as its data.
+//   - Json is the body written in YAML, where string values of the form ${ expr } are replaced by the result of the expression.
+//   - Form is a map where keys represent the fields of a form-urlencoded body,
+//     and values are expressions evaluated at runtime. A list value sends the field once for each item.
+//   - Multipart defines the fields and files of a multipart/form-data body.
 type RequestBody struct {
-	Template *template.Template // Raw JSON payload.
-	Data     map[string]string  // Dynamic fields evaluated and added/updated in Content.
+	Template  *template.Template // Raw JSON payload.
+	Data      map[string]string  // Dynamic fields evaluated and added/updated in Content.
+	Json      any                // JSON payload with expressions in its values.
+	Form      map[string]string  // Fields of a form-urlencoded body mapped to expressions.
+	Multipart *MultipartBody     // Fields and files of a multipart/form-data body.
+}
+
+// MultipartBody represents the parts of a multipart/form-data body.
+type MultipartBody struct {
+	Fields map[string]string // Fields mapped to expressions. A list value sends the field once for each item.
+	Files  map[string]string // Fields mapped to the paths of the files to send, relative to the configuration.
 }
 
 const MainTemplateName = "request"
 
 func (rb *RequestBody) UnmarshalYAML(value *yaml.Node) error {
 	var rawRb struct {
-		Template string
-		File     string
-		Data     map[string]string
+		Template  string
+		File      string
+		Data      map[string]string
+		Json      any
+		Form      map[string]string
+		Multipart *MultipartBody
 	}
 
 	if err := value.Decode(&rawRb); err != nil {
@@ -218,12 +412,47 @@ func (rb *RequestBody) UnmarshalYAML(value *yaml.Node) error {
 	}
 
 	*rb = RequestBody{
-		Template: nil,
-		Data:     rawRb.Data,
+		Template:  nil,
+		Data:      rawRb.Data,
+		Json:      rawRb.Json,
+		Form:      rawRb.Form,
+		Multipart: rawRb.Multipart,
+	}
+
+	modes := 0
+	for _, set := range []bool{rawRb.Template != "" || rawRb.File != "", rawRb.Json != nil, rawRb.Form != nil, rawRb.Multipart != nil} {
+		if set {
+			modes++
+		}
 	}
 
-	if rawRb.Template == "" && rawRb.File == "" {
-		return fmt.Errorf("template or file should be provided for request body")
+	if modes == 0 {
+		return fmt.Errorf("template, file, json, form or multipart should be provided for request body")
+	}
+
+	if modes > 1 {
+		return fmt.Errorf("only one of template or file, json, form and multipart should be provided for request body")
+	}
+
+	if rawRb.Data != nil && rawRb.Template == "" && rawRb.File == "" && rawRb.Json == nil {
+		return fmt.Errorf("data is only supported with template, file or json for request body")
+	}
+
+	if rawRb.Multipart != nil {
+		for field, path := range rawRb.Multipart.Files {
+			file, err := filepath.Abs(path)
+			if err != nil {
+				return fmt.Errorf("request body file for field '%s': %v", field, err)
+			}
+
+			rawRb.Multipart.Files[field] = file
+		}
+
+		return nil
+	}
+
+	if rawRb.Form != nil || rawRb.Json != nil {
+		return nil
 	}
 
 	if rawRb.Template != "" {
@@ -251,30 +480,434 @@ func (rb *RequestBody) UnmarshalYAML(value *yaml.Node) error {
 	return fmt.Errorf("no template provided for request body")
 }
 
-// build builds the request body with rb.Content as the base and updates the field values after evaluating expressions in rb.Data.
-func (rb *RequestBody) build(context *ExecutionContext) ([]byte, error) {
+// build builds the request body and returns it along with its content type.
+// The content type is empty for templates, whose content type is set by the headers of the request.
+// The fields in rb.Data are set in the JSON body after it is built from the template or rb.Json.
+func (rb *RequestBody) build(context *ExecutionContext) ([]byte, string, error) {
 	if rb == nil {
-		return nil, nil
+		return nil, "", nil
 	}
 
-	vars := context.store.Map()
+	vars := context.variables()
 
-	tvars := map[string]any{}
+	switch {
+	case rb.Json != nil:
+		val, err := evalJson(rb.Json, vars, "")
+		if err != nil {
+			return nil, "", err
+		}
 
-	for v, e := range rb.Data {
-		val, err := expr.Eval(e, vars)
+		body, err := json.Marshal(val)
 		if err != nil {
-			return nil, fmt.Errorf("expression '%s' cannot be evaluated for variable '%s': %v", e, v, err)
+			return nil, "", fmt.Errorf("json body cannot be encoded: %v", err)
+		}
+
+		patches, err := evalData(rb.Data, vars)
+		if err != nil {
+			return nil, "", err
+		}
+
+		body, err = patchBody(body, patches)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return body, "application/json", nil
+	case rb.Form != nil:
+		fields, err := evalFields(rb.Form, vars)
+		if err != nil {
+			return nil, "", err
+		}
+
+		body, contentType := http.EncodeForm(fields)
+
+		return body, contentType, nil
+	case rb.Multipart != nil:
+		fields, err := evalFields(rb.Multipart.Fields, vars)
+		if err != nil {
+			return nil, "", err
 		}
 
-		tvars[v] = val
+		names := slices.Sorted(maps.Keys(rb.Multipart.Files))
+		files := make([]http.FilePart, 0, len(names))
+
+		for _, name := range names {
+			files = append(files, http.FilePart{Field: name, Path: rb.Multipart.Files[name]})
+		}
+
+		return http.EncodeMultipart(fields, files)
+	}
+
+	body, err := rb.buildTemplate(vars)
+
+	return body, "", err
+}
+
+// buildTemplate builds the request body with rb.Content as the base and updates the field values after evaluating expressions in rb.Data.
+// The fields used by the template are passed to it as its data, while the other fields are set at their path in the body
+// if the template produces JSON.
+func (rb *RequestBody) buildTemplate(vars map[string]any) ([]byte, error) {
+	tvars, err := evalData(rb.Data, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	// Templates loaded from a file define the main template by name, while inline templates are the main template.
+	tmpl := rb.Template
+	if t := tmpl.Lookup(MainTemplateName); t != nil {
+		tmpl = t
 	}
 
 	var buf bytes.Buffer
-	err := rb.Template.ExecuteTemplate(&buf, MainTemplateName, tvars)
+	err = tmpl.Execute(&buf, tvars)
 	if err != nil {
 		return nil, fmt.Errorf("error executing template: %v", err)
 	}
 
-	return buf.Bytes(), nil
+	body := buf.Bytes()
+
+	// Only JSON bodies can be patched, other templates use the data as before.
+	if !json.Valid(body) {
+		return body, nil
+	}
+
+	refs := templateRefs{fields: map[string]bool{}}
+	for _, t := range rb.Template.Templates() {
+		if t.Tree != nil {
+			refs.walk(t.Tree.Root, false)
+		}
+	}
+
+	if refs.all {
+		return body, nil
+	}
+
+	patches := map[string]any{}
+	for path, val := range tvars {
+		if !refs.fields[path] {
+			patches[path] = val
+		}
+	}
+
+	return patchBody(body, patches)
+}
+
+// patchBody sets each value at its sjson path in the JSON body, in the order of the paths.
+func patchBody(body []byte, values map[string]any) ([]byte, error) {
+	for _, path := range slices.Sorted(maps.Keys(values)) {
+		var err error
+
+		body, err = sjson.SetBytes(body, path, values[path])
+		if err != nil {
+			return nil, fmt.Errorf("unable to set path '%s' in request body: %v", path, err)
+		}
+	}
+
+	return body, nil
+}
+
+// evalData evaluates the expressions of the fields in data using vars.
+func evalData(data map[string]string, vars map[string]any) (map[string]any, error) {
+	values := make(map[string]any, len(data))
+
+	for v, e := range data {
+		val, err := expr.Eval(e, vars)
+		if err != nil {
+			return nil, fmt.Errorf("expression '%s' cannot be evaluated for variable '%s': %v", e, v, err)
+		}
+
+		values[v] = val
+	}
+
+	return values, nil
+}
+
+// templateRefs collects the top-level fields of the data used by a template.
+type templateRefs struct {
+	fields map[string]bool // the names of the fields used by the template
+	all    bool            // whether the template uses the data as a whole, such as with {{ . }}
+}
+
+// walk collects the fields used by node. nested is true within range and with blocks, where dot refers to another value.
+func (r *templateRefs) walk(node parse.Node, nested bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+
+		for _, child := range n.Nodes {
+			r.walk(child, nested)
+		}
+	case *parse.ActionNode:
+		r.walk(n.Pipe, nested)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+
+		for _, cmd := range n.Cmds {
+			r.walk(cmd, nested)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			r.walk(arg, nested)
+		}
+	case *parse.ChainNode:
+		r.walk(n.Node, nested)
+	case *parse.FieldNode:
+		r.fields[n.Ident[0]] = true
+	case *parse.VariableNode:
+		if n.Ident[0] == "$" {
+			if len(n.Ident) == 1 {
+				r.all = true
+			} else {
+				r.fields[n.Ident[1]] = true
+			}
+		}
+	case *parse.DotNode:
+		if !nested {
+			r.all = true
+		}
+	case *parse.IfNode:
+		r.walk(n.Pipe, nested)
+		r.walk(n.List, nested)
+		r.walk(n.ElseList, nested)
+	case *parse.RangeNode:
+		r.walk(n.Pipe, nested)
+		r.walk(n.List, true)
+		r.walk(n.ElseList, nested)
+	case *parse.WithNode:
+		r.walk(n.Pipe, nested)
+		r.walk(n.List, true)
+		r.walk(n.ElseList, nested)
+	case *parse.TemplateNode:
+		// Passing the data to another template does not use it, the fields used by that template are collected separately.
+		if n.Pipe != nil && len(n.Pipe.Cmds) == 1 && len(n.Pipe.Cmds[0].Args) == 1 {
+			if _, ok := n.Pipe.Cmds[0].Args[0].(*parse.DotNode); ok {
+				return
+			}
+		}
+
+		r.walk(n.Pipe, nested)
+	}
+}
+
+// evalJson evaluates the expressions in the values of a JSON body decoded from YAML, using vars.
+// A string that is a single ${ expr } is replaced by the result of the expression, preserving its type,
+// while expressions within a string are replaced by their results formatted as text.
+// The path of the value is used in errors.
+func evalJson(val any, vars map[string]any, path string) (any, error) {
+	switch v := val.(type) {
+	case string:
+		return evalJsonString(v, vars, path)
+	case map[string]any:
+		result := make(map[string]any, len(v))
+
+		for key, item := range v {
+			eval, err := evalJson(item, vars, joinJsonPath(path, key))
+			if err != nil {
+				return nil, err
+			}
+
+			result[key] = eval
+		}
+
+		return result, nil
+	case map[any]any:
+		result := make(map[string]any, len(v))
+
+		for key, item := range v {
+			name := fmt.Sprint(key)
+
+			eval, err := evalJson(item, vars, joinJsonPath(path, name))
+			if err != nil {
+				return nil, err
+			}
+
+			result[name] = eval
+		}
+
+		return result, nil
+	case []any:
+		result := make([]any, len(v))
+
+		for i, item := range v {
+			eval, err := evalJson(item, vars, joinJsonPath(path, fmt.Sprint(i)))
+			if err != nil {
+				return nil, err
+			}
+
+			result[i] = eval
+		}
+
+		return result, nil
+	}
+
+	return val, nil
+}
+
+// evalJsonString evaluates the expressions in a string value of a JSON body.
+func evalJsonString(s string, vars map[string]any, path string) (any, error) {
+	segments, err := splitJsonString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression in json body at '%s': %v", path, err)
+	}
+
+	if len(segments) == 1 && segments[0].expr {
+		e := segments[0].text
+
+		val, err := expr.Eval(e, vars)
+		if err != nil {
+			return nil, fmt.Errorf("expression '%s' cannot be evaluated for json body at '%s': %v", e, path, err)
+		}
+
+		return val, nil
+	}
+
+	var sb strings.Builder
+
+	for _, seg := range segments {
+		if !seg.expr {
+			sb.WriteString(seg.text)
+			continue
+		}
+
+		val, err := expr.Eval(seg.text, vars)
+		if err != nil {
+			return nil, fmt.Errorf("expression '%s' cannot be evaluated for json body at '%s': %v", seg.text, path, err)
+		}
+
+		if str, ok := val.(string); ok {
+			sb.WriteString(str)
+			continue
+		}
+
+		text, err := json.Marshal(val)
+		if err != nil {
+			return nil, fmt.Errorf("expression '%s' cannot be encoded for json body at '%s': %v", seg.text, path, err)
+		}
+
+		sb.Write(text)
+	}
+
+	return sb.String(), nil
+}
+
+// jsonSegment is a part of a string value of a JSON body, either text or the source of an expression.
+type jsonSegment struct {
+	text string
+	expr bool
+}
+
+// splitJsonString splits s into text and ${ expr } expressions. $${ is written as a literal ${.
+// The expressions may contain braces, such as predicates and map literals, and quoted strings.
+func splitJsonString(s string) ([]jsonSegment, error) {
+	var segments []jsonSegment
+	var text strings.Builder
+
+	for i := 0; i < len(s); {
+		if strings.HasPrefix(s[i:], "$${") {
+			text.WriteString("${")
+			i += 3
+			continue
+		}
+
+		if !strings.HasPrefix(s[i:], "${") {
+			text.WriteByte(s[i])
+			i++
+			continue
+		}
+
+		end, err := exprEnd(s, i+2)
+		if err != nil {
+			return nil, err
+		}
+
+		e := strings.TrimSpace(s[i+2 : end])
+		if e == "" {
+			return nil, fmt.Errorf("empty expression at offset %d", i)
+		}
+
+		if text.Len() > 0 {
+			segments = append(segments, jsonSegment{text: text.String()})
+			text.Reset()
+		}
+
+		segments = append(segments, jsonSegment{text: e, expr: true})
+		i = end + 1
+	}
+
+	if text.Len() > 0 {
+		segments = append(segments, jsonSegment{text: text.String()})
+	}
+
+	return segments, nil
+}
+
+// exprEnd returns the index of the '}' that closes the expression starting at start in s.
+// Nested braces are counted and braces within quoted strings are ignored.
+func exprEnd(s string, start int) (int, error) {
+	depth := 1
+
+	for i := start; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\'', '`':
+			j := i + 1
+			for ; j < len(s) && s[j] != c; j++ {
+				if s[j] == '\\' && c != '`' {
+					j++
+				}
+			}
+
+			if j >= len(s) {
+				return 0, fmt.Errorf("unterminated string in expression '%s'", s[start:])
+			}
+
+			i = j
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("missing '}' for expression '%s'", s[start:])
+}
+
+// joinJsonPath returns the path of key within the value at path.
+func joinJsonPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+// evalFields evaluates the expressions of form fields using vars.
+// A list value results in a value for each item, and any other value is formatted as a string.
+func evalFields(fieldExprs map[string]string, vars map[string]any) (map[string][]string, error) {
+	fields := make(map[string][]string, len(fieldExprs))
+
+	for name, e := range fieldExprs {
+		val, err := expr.Eval(e, vars)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression '%s' for field '%s': %v", e, name, err)
+		}
+
+		rv := reflect.ValueOf(val)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			fields[name] = []string{fmt.Sprint(val)}
+			continue
+		}
+
+		for i := range rv.Len() {
+			fields[name] = append(fields[name], fmt.Sprint(rv.Index(i).Interface()))
+		}
+	}
+
+	return fields, nil
 }
@@ -2,11 +2,20 @@ package workflow_test
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 	"text/template"
+	"time"
 
 	"github.com/santhanuv/srotas/internal/http"
 	"github.com/santhanuv/srotas/internal/log"
@@ -93,7 +102,7 @@ type mockHttpClient struct {
 
 var ErrFailedHttpRequestValidation = errors.New("validation of http request failed while testing")
 
-func (m *mockHttpClient) Do(req *http.Request) (*http.Response, error) {
+func (m *mockHttpClient) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
 	if err := m.validator(req); err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrFailedHttpRequestValidation, err)
 	}
@@ -256,3 +265,679 @@ func TestHttpRequest_Execute_GetWithResponseBody(t *testing.T) {
 		t.Fatalf("expected string %q; but got '%v'\nLogs: %s", "test name", name, logBuf.String())
 	}
 }
+
+// sequenceHttpClient is a mock http client that responds with the given responses in order.
+type sequenceHttpClient struct {
+	responses []*http.Response
+	calls     int
+}
+
+func (s *sequenceHttpClient) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if s.calls >= len(s.responses) {
+		return nil, errors.New("no more responses")
+	}
+
+	res := s.responses[s.calls]
+	s.calls++
+
+	return res, nil
+}
+
+func TestHttpRequest_Execute_Retry(t *testing.T) {
+	unavailable := &http.Response{StatusCode: 503, Status: "503 Service Unavailable", Body: []byte(`{}`)}
+	ok := &http.Response{StatusCode: 200, Status: "200 OK", Body: []byte(`{"Id":"123"}`)}
+
+	tests := []struct {
+		name      string
+		retry     *workflow.Retry
+		responses []*http.Response
+		calls     int
+		err       bool
+	}{
+		{
+			name:      "succeeds after retrying on matching condition",
+			retry:     &workflow.Retry{MaxAttempts: 3, RetryOn: "res.status == 503"},
+			responses: []*http.Response{unavailable, unavailable, ok},
+			calls:     3,
+		},
+		{
+			name:      "succeeds after retrying on validation failure",
+			retry:     &workflow.Retry{MaxAttempts: 2, Backoff: workflow.ExponentialBackoff},
+			responses: []*http.Response{unavailable, ok},
+			calls:     2,
+		},
+		{
+			name:      "fails once attempts are exhausted",
+			retry:     &workflow.Retry{MaxAttempts: 2, RetryOn: "res.status == 503"},
+			responses: []*http.Response{unavailable, unavailable, ok},
+			calls:     2,
+			err:       true,
+		},
+		{
+			name:      "condition uses variables with the names of response fields",
+			retry:     &workflow.Retry{MaxAttempts: 3, RetryOn: "status == 'pending' && res.status == 503"},
+			responses: []*http.Response{unavailable, ok},
+			calls:     2,
+		},
+		{
+			name:      "does not retry when condition does not match",
+			retry:     &workflow.Retry{MaxAttempts: 3, RetryOn: "res.status == 500"},
+			responses: []*http.Response{unavailable, ok},
+			calls:     1,
+			err:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		req := workflow.Request{
+			Type:     "http",
+			StepName: "Http request",
+			Url:      "test",
+			Method:   "GET",
+			Store:    map[string]string{"id": "response.Id"},
+			Validations: &workflow.Validator{
+				Status_code: 200,
+			},
+			Retry: tt.retry,
+		}
+
+		client := &sequenceHttpClient{responses: tt.responses}
+		mockStore := store.NewStore(map[string]any{"status": "pending"})
+
+		logBuf := bytes.NewBuffer(nil)
+		logger := log.New(logBuf, logBuf, logBuf)
+
+		execContext, err := workflow.NewExecutionContext(workflow.WithGlobalOptions("https://domain.com", nil),
+			workflow.WithHttpClient(client),
+			workflow.WithStore(mockStore),
+			workflow.WithLogger(logger))
+		if err != nil {
+			t.Fatalf("failed to setup test: unable to create execution context")
+		}
+
+		err = req.Execute(execContext)
+
+		if tt.err && err == nil {
+			t.Errorf("in test %q; expected error but got none", tt.name)
+		}
+
+		if !tt.err && err != nil {
+			t.Errorf("in test %q; expected no error but got %q\nLogs: %s", tt.name, err, logBuf.String())
+		}
+
+		if client.calls != tt.calls {
+			t.Errorf("in test %q; expected %d attempts but got %d", tt.name, tt.calls, client.calls)
+		}
+
+		if id, _ := mockStore.Get("id"); !tt.err && id != "123" {
+			t.Errorf("in test %q; expected 'id' to be stored from the final response but got '%v'", tt.name, id)
+		}
+	}
+}
+
+func TestHttpRequest_Execute_Timeout(t *testing.T) {
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		select {
+		case <-time.After(500 * time.Millisecond):
+		case <-r.Context().Done():
+		}
+
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		clientTimeout uint
+		stepTimeout   uint
+		err           string
+	}{
+		{
+			name:          "config timeout is exceeded",
+			clientTimeout: 50,
+			err:           "exceeded the config timeout of 50ms",
+		},
+		{
+			name:        "step timeout is exceeded",
+			stepTimeout: 50,
+			err:         "exceeded the step timeout of 50ms",
+		},
+		{
+			name:          "step timeout overrides config timeout",
+			clientTimeout: 50,
+			stepTimeout:   2000,
+		},
+	}
+
+	for _, tt := range tests {
+		req := workflow.Request{
+			Type:     "http",
+			StepName: "Http request",
+			Url:      server.URL,
+			Method:   "GET",
+			Timeout:  tt.stepTimeout,
+		}
+
+		logBuf := bytes.NewBuffer(nil)
+
+		execContext, err := workflow.NewExecutionContext(
+			workflow.WithHttpClient(http.NewClient(tt.clientTimeout)),
+			workflow.WithLogger(log.New(logBuf, logBuf, logBuf)))
+		if err != nil {
+			t.Fatalf("failed to setup test: unable to create execution context")
+		}
+
+		err = req.Execute(execContext)
+
+		if tt.err == "" {
+			if err != nil {
+				t.Errorf("in test %q; expected no error but got %q", tt.name, err)
+			}
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("in test %q; expected error containing %q but got %v", tt.name, tt.err, err)
+		}
+	}
+}
+
+func TestHttpRequest_Execute_ResponseFormat(t *testing.T) {
+	tests := map[string]struct {
+		contentType string
+		body        string
+		format      string
+		want        any
+		err         bool
+	}{
+		"json": {
+			contentType: "application/problem+json",
+			body:        `{"id": 1}`,
+			want:        map[string]any{"id": float64(1)},
+		},
+		"xml": {
+			contentType: "text/xml; charset=utf-8",
+			body:        `<user id="1"><name>alice</name><role>admin</role><role>dev</role></user>`,
+			want: map[string]any{"user": map[string]any{
+				"@id":  "1",
+				"name": "alice",
+				"role": []any{"admin", "dev"},
+			}},
+		},
+		"text": {
+			contentType: "text/plain",
+			body:        "OK",
+			want:        "OK",
+		},
+		"html": {
+			contentType: "text/html",
+			body:        "<p>OK",
+			want:        "<p>OK",
+		},
+		"binary": {
+			contentType: "application/octet-stream",
+			body:        "abc",
+			want: map[string]any{
+				"content":      "YWJj",
+				"size":         3,
+				"sha256":       "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+				"content_type": "application/octet-stream",
+			},
+		},
+		"empty": {
+			want: nil,
+		},
+		"json without content type": {
+			body: `[1]`,
+			want: []any{float64(1)},
+		},
+		"response_format overrides content type": {
+			contentType: "text/plain",
+			body:        `{"id": 1}`,
+			format:      "json",
+			want:        map[string]any{"id": float64(1)},
+		},
+		"invalid json": {
+			contentType: "application/json",
+			body:        "OK",
+			err:         true,
+		},
+	}
+
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		tt := tests[r.URL.Query().Get("test")]
+		if tt.contentType != "" {
+			w.Header().Set("Content-Type", tt.contentType)
+		} else {
+			// Prevents the server from detecting the content type.
+			w.Header()["Content-Type"] = nil
+		}
+
+		if tt.body == "" {
+			w.WriteHeader(nethttp.StatusNoContent)
+			return
+		}
+
+		w.Write([]byte(tt.body))
+	}))
+	defer server.Close()
+
+	for name, tt := range tests {
+		req := workflow.Request{
+			Type:           "http",
+			StepName:       "Http request",
+			Url:            server.URL,
+			QueryParams:    &workflow.QueryParam{"test": {fmt.Sprintf("'%s'", name)}},
+			Method:         "GET",
+			ResponseFormat: tt.format,
+			Store:          map[string]string{"body": "response", "raw": "raw_response"},
+		}
+
+		s := store.NewStore(nil)
+		logBuf := bytes.NewBuffer(nil)
+
+		execContext, err := workflow.NewExecutionContext(
+			workflow.WithStore(s),
+			workflow.WithHttpClient(http.NewClient(0)),
+			workflow.WithLogger(log.New(logBuf, logBuf, logBuf)))
+		if err != nil {
+			t.Fatalf("failed to setup test: unable to create execution context")
+		}
+
+		err = req.Execute(execContext)
+
+		if tt.err {
+			if err == nil {
+				t.Errorf("in test %q; expected error but got none", name)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("in test %q; expected no error but got %q", name, err)
+			continue
+		}
+
+		if got, _ := s.Get("body"); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("in test %q; expected response %#v but got %#v", name, tt.want, got)
+		}
+
+		if got, _ := s.Get("raw"); got != tt.body {
+			t.Errorf("in test %q; expected raw response %q but got %q", name, tt.body, got)
+		}
+	}
+}
+
+func TestHttpRequest_Execute_ResponseObject(t *testing.T) {
+	mux := nethttp.NewServeMux()
+	mux.HandleFunc("/old", func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		nethttp.Redirect(w, r, "/users", nethttp.StatusFound)
+	})
+	mux.HandleFunc("/users", func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		w.Header().Set("Location", "/users/1")
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Content-Type", "application/json")
+		nethttp.SetCookie(w, &nethttp.Cookie{Name: "session", Value: "abc"})
+		w.WriteHeader(nethttp.StatusCreated)
+		w.Write([]byte(`{"id": 1}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		asserts []workflow.Assert
+		vars    map[string]any
+		store   map[string]string
+		want    map[string]any
+		err     string
+	}{
+		{
+			name: "status, headers, cookies and url",
+			asserts: []workflow.Assert{
+				"res.status == 201",
+				"res.Header('CACHE-CONTROL') == 'no-store'",
+				"res.url endsWith '/users'",
+				"res.elapsed >= 0",
+				"res.body.id == response.id",
+			},
+			store: map[string]string{
+				"location": "res.headers.location",
+				"session":  "res.cookies.session",
+				"raw":      "res.raw",
+			},
+			want: map[string]any{"location": "/users/1", "session": "abc", "raw": `{"id": 1}`},
+		},
+		{
+			name: "store into the response variables",
+			store: map[string]string{
+				"res":          "response.id",
+				"raw_response": "response.id",
+				"other":        "response.id",
+			},
+			want: map[string]any{"res": 1.0, "raw_response": 1.0, "other": 1.0},
+		},
+		{
+			name:    "restore variables defined before the step",
+			asserts: []workflow.Assert{"res.status == 201", "response.id == 1"},
+			vars:    map[string]any{"res": "kept", "response": "previous"},
+			want:    map[string]any{"res": "kept", "response": "previous"},
+		},
+		{
+			name:    "error includes the headers of the response",
+			asserts: []workflow.Assert{"res.status == 200"},
+			err:     `"location": "/users/1"`,
+		},
+	}
+
+	for _, tt := range tests {
+		req := workflow.Request{
+			Type:        "http",
+			StepName:    "Http request",
+			Url:         server.URL + "/old",
+			Method:      "POST",
+			Store:       tt.store,
+			Validations: &workflow.Validator{Asserts: tt.asserts},
+		}
+
+		s := store.NewStore(tt.vars)
+		logBuf := bytes.NewBuffer(nil)
+
+		execContext, err := workflow.NewExecutionContext(
+			workflow.WithStore(s),
+			workflow.WithHttpClient(http.NewClient(0)),
+			workflow.WithLogger(log.New(logBuf, logBuf, logBuf)))
+		if err != nil {
+			t.Fatalf("failed to setup test: unable to create execution context")
+		}
+
+		err = req.Execute(execContext)
+
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("in test %q; expected error containing %q but got %v", tt.name, tt.err, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("in test %q; expected no error but got %q", tt.name, err)
+			continue
+		}
+
+		for name, want := range tt.want {
+			if got, _ := s.Get(name); got != want {
+				t.Errorf("in test %q; expected '%s' to be %v but got %v", tt.name, name, want, got)
+			}
+		}
+
+		for _, name := range []string{"response", "raw_response", "res"} {
+			if _, ok := tt.want[name]; ok {
+				continue
+			}
+
+			if _, ok := s.Get(name); ok {
+				t.Errorf("in test %q; expected '%s' to be removed after the step", tt.name, name)
+			}
+		}
+	}
+}
+
+func TestHttpRequest_Execute_FormBody(t *testing.T) {
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil && !errors.Is(err, nethttp.ErrNotMultipart) {
+			nethttp.Error(w, err.Error(), nethttp.StatusBadRequest)
+			return
+		}
+
+		received := map[string]any{"fields": r.Form}
+
+		if r.MultipartForm != nil {
+			for field, headers := range r.MultipartForm.File {
+				f, _ := headers[0].Open()
+				content, _ := io.ReadAll(f)
+				f.Close()
+
+				received[field] = map[string]any{
+					"filename":     headers[0].Filename,
+					"content_type": headers[0].Header.Get("Content-Type"),
+					"content":      string(content),
+				}
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(received)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "users.csv"), []byte("id\n1\n"), 0o644); err != nil {
+		t.Fatalf("failed to setup test: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		body    string
+		asserts []workflow.Assert
+	}{
+		{
+			name: "form-urlencoded body",
+			body: `
+form:
+  user: "name"
+  tags: "['a', 'b']"`,
+			asserts: []workflow.Assert{
+				"response.fields.user == ['alice']",
+				"response.fields.tags == ['a', 'b']",
+			},
+		},
+		{
+			name: "multipart body with a file relative to the config",
+			body: `
+multipart:
+  fields:
+    user: "name"
+  files:
+    import: "users.csv"`,
+			asserts: []workflow.Assert{
+				"response.fields.user == ['alice']",
+				"response.import.filename == 'users.csv'",
+				"response.import.content_type startsWith 'text/csv'",
+				"response.import.content == 'id\\n1\\n'",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		asserts, _ := json.Marshal(tt.asserts)
+
+		config := fmt.Sprintf(`
+base_url: %q
+steps:
+  - type: http
+    step:
+      name: submit
+      method: POST
+      url: /submit
+      body:%s
+      validations:
+        status_code: 200
+        asserts: %s
+`, server.URL, strings.ReplaceAll(tt.body, "\n", "\n        "), asserts)
+
+		path := filepath.Join(dir, "config.yaml")
+		if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+			t.Fatalf("failed to setup test: %v", err)
+		}
+
+		logBuf := bytes.NewBuffer(nil)
+		logger := log.New(logBuf, logBuf, logBuf)
+
+		def, err := workflow.ParseConfig(path, logger)
+		if err != nil {
+			t.Errorf("in test %q; expected config to be parsed but got %v", tt.name, err)
+			continue
+		}
+
+		execContext, err := workflow.NewExecutionContext(
+			workflow.WithStore(store.NewStore(map[string]any{"name": "alice"})),
+			workflow.WithGlobalOptions(def.BaseUrl, map[string][]string{"Content-Type": {"application/json"}}),
+			workflow.WithHttpClient(http.NewClient(0)),
+			workflow.WithLogger(logger))
+		if err != nil {
+			t.Fatalf("failed to setup test: unable to create execution context")
+		}
+
+		if err := workflow.Execute(def, execContext); err != nil {
+			t.Errorf("in test %q; expected no error but got %v", tt.name, err)
+		}
+	}
+}
+
+func TestHttpRequest_Execute_JsonBody(t *testing.T) {
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		var body any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			nethttp.Error(w, err.Error(), nethttp.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"content_type": r.Header.Get("Content-Type"), "body": body})
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		body    string
+		asserts []workflow.Assert
+	}{
+		{
+			name: "json body with typed expressions",
+			body: `
+json:
+  id: "${ user.id }"
+  active: "${user.active}"
+  roles: "${ user.roles }"
+  greeting: "Hello ${ user.name }, you are ${ user.id }"
+  profile:
+    name: "${ user.name }"
+    tags: ["static", "${ user.roles[0] }"]
+  fixed: 10`,
+			asserts: []workflow.Assert{
+				"response.content_type == 'application/json'",
+				"response.body.id == 42",
+				"response.body.active == true",
+				"response.body.roles == ['admin', 'dev']",
+				"response.body.greeting == 'Hello alice, you are 42'",
+				"response.body.profile.name == 'alice'",
+				"response.body.profile.tags == ['static', 'admin']",
+				"response.body.fixed == 10",
+			},
+		},
+		{
+			name: "json body with braces in expressions",
+			body: `
+json:
+  admins: "${ filter(user.roles, {# != 'dev'}) }"
+  lengths: "${ map(user.roles, {len(#)}) }"
+  map: "${ {'a': 1, 'b': {'c': user.id}} }"
+  quoted: "${ '}' + \"{\" + user.name }"
+  text: "roles: ${ filter(user.roles, {# == 'admin'}) } of ${ user.name }"
+  literal: "$${ user.id } is ${ user.id }"`,
+			asserts: []workflow.Assert{
+				"response.body.admins == ['admin']",
+				"response.body.lengths == [5, 3]",
+				"response.body.map.a == 1",
+				"response.body.map.b.c == 42",
+				"response.body.quoted == '}{alice'",
+				`response.body.text == 'roles: ["admin"] of alice'`,
+				"response.body.literal == '${ user.id } is 42'",
+			},
+		},
+		{
+			name: "json body with data patches",
+			body: `
+json:
+  user:
+    name: "${ user.name }"
+data:
+  user.id: "user.id"
+  meta.tags: "user.roles"`,
+			asserts: []workflow.Assert{
+				"response.body.user.name == 'alice'",
+				"response.body.user.id == 42",
+				"response.body.meta.tags == ['admin', 'dev']",
+			},
+		},
+		{
+			name: "template with data used by the template and patches",
+			body: `
+template: '{"Name": "{{ .name }}", "items": [{"id": 1}]}'
+data:
+  name: "user.name"
+  items.0.owner: "user.id"
+  items.-1: "{id: 2}"`,
+			asserts: []workflow.Assert{
+				"response.body.Name == 'alice'",
+				"response.body.name == nil",
+				"response.body.items[0].owner == 42",
+				"response.body.items[1].id == 2",
+				"len(response.body.items) == 2",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		asserts, _ := json.Marshal(tt.asserts)
+
+		config := fmt.Sprintf(`
+base_url: %q
+steps:
+  - type: http
+    step:
+      name: submit
+      method: POST
+      url: /submit
+      body:%s
+      validations:
+        status_code: 200
+        asserts: %s
+`, server.URL, strings.ReplaceAll(tt.body, "\n", "\n        "), asserts)
+
+		path := filepath.Join(dir, "config.yaml")
+		if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+			t.Fatalf("failed to setup test: %v", err)
+		}
+
+		logBuf := bytes.NewBuffer(nil)
+		logger := log.New(logBuf, logBuf, logBuf)
+
+		def, err := workflow.ParseConfig(path, logger)
+		if err != nil {
+			t.Errorf("in test %q; expected config to be parsed but got %v", tt.name, err)
+			continue
+		}
+
+		vars := map[string]any{
+			"user": map[string]any{"id": 42, "name": "alice", "active": true, "roles": []any{"admin", "dev"}},
+		}
+
+		execContext, err := workflow.NewExecutionContext(
+			workflow.WithStore(store.NewStore(vars)),
+			workflow.WithGlobalOptions(def.BaseUrl, map[string][]string{"Content-Type": {"text/plain"}}),
+			workflow.WithHttpClient(http.NewClient(0)),
+			workflow.WithLogger(logger))
+		if err != nil {
+			t.Fatalf("failed to setup test: unable to create execution context")
+		}
+
+		if err := workflow.Execute(def, execContext); err != nil {
+			t.Errorf("in test %q; expected no error but got %v", tt.name, err)
+		}
+	}
+}
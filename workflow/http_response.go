@@ -2,13 +2,55 @@ package workflow
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/expr-lang/expr"
+	"github.com/santhanuv/srotas/internal/http"
 )
 
+// Response represents an HTTP response, available to the expressions of http and poll steps as the res variable.
+type Response struct {
+	Status  uint              `expr:"status" json:"status"`   // Status code of the response.
+	Headers map[string]string `expr:"headers" json:"headers"` // Headers of the response by lowercase name, with multiple values joined by ", ".
+	Cookies map[string]string `expr:"cookies" json:"cookies"` // Values of the cookies set by the response by name.
+	Elapsed int64             `expr:"elapsed" json:"elapsed"` // Time (milliseconds) taken to send the request and read the response.
+	Url     string            `expr:"url" json:"url"`         // URL of the response, after following redirects.
+	Body    any               `expr:"body" json:"body"`       // Decoded body of the response.
+	Raw     string            `expr:"raw" json:"-"`           // Body of the response as a string.
+}
+
+// newResponse returns the [Response] for res with the decoded body.
+func newResponse(res *http.Response, body any) *Response {
+	response := Response{
+		Status:  res.StatusCode,
+		Headers: make(map[string]string, len(res.Headers)),
+		Cookies: make(map[string]string, len(res.Cookies)),
+		Elapsed: res.Elapsed.Milliseconds(),
+		Url:     res.Url,
+		Body:    body,
+		Raw:     string(res.Body),
+	}
+
+	for name, values := range res.Headers {
+		response.Headers[strings.ToLower(name)] = strings.Join(values, ", ")
+	}
+
+	for _, cookie := range res.Cookies {
+		response.Cookies[cookie.Name] = cookie.Value
+	}
+
+	return &response
+}
+
+// Header returns the value of the header name, matched case-insensitively.
+func (r *Response) Header(name string) string {
+	return r.Headers[strings.ToLower(name)]
+}
+
 // responseBody represents the response body obtained after executing the Request step.
 type responseBody struct {
-	body any `expr:"response"` // The json response from executing HTTP request.
+	body any       `expr:"response"` // The json response from executing HTTP request.
+	res  *Response // The response from executing HTTP request.
 }
 
 // store stores the new set of variables after evaluating the variable expressions in varExprs
@@ -21,7 +63,7 @@ func (rb *responseBody) store(varExprs map[string]string, context *ExecutionCont
 
 	newVars := make(map[string]any, len(varExprs))
 
-	vars := context.store.Map()
+	vars := context.variables()
 
 	for vn, ve := range varExprs {
 		val, err := expr.Eval(ve, vars)
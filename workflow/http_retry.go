@@ -0,0 +1,105 @@
+package workflow
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/santhanuv/srotas/internal/http"
+)
+
+const (
+	FixedBackoff       = "fixed"       // Waits the same delay between every attempt.
+	ExponentialBackoff = "exponential" // Doubles the delay after every attempt.
+)
+
+// DefaultMaxDelay is the upper bound for the wait time between attempts when max_delay is not provided.
+const DefaultMaxDelay = time.Hour
+
+// Retry represents the retry policy for an HTTP request step.
+// A failed attempt is retried when the request could not be sent, or when RetryOn evaluates to true.
+// If RetryOn is not provided, an attempt whose response fails validation is also retried.
+type Retry struct {
+	MaxAttempts uint   `yaml:"max_attempts"` // Maximum number of attempts, including the first one.
+	Backoff     string // Backoff strategy between attempts, either fixed or exponential.
+	Delay       uint   // Wait time (milliseconds) before the first retry.
+	MaxDelay    uint   `yaml:"max_delay"` // Upper bound (milliseconds) for the wait time between attempts.
+	Jitter      bool   // Randomizes the wait time between attempts if true.
+	RetryOn     string `yaml:"retry_on"` // Expression evaluated on the response that determines whether to retry.
+}
+
+// Validate checks the fields of the [Retry] policy and returns an error for the first invalid field.
+func (r *Retry) Validate() error {
+	if r == nil {
+		return nil
+	}
+
+	if r.MaxAttempts == 0 {
+		return RequiredFieldError{Field: "retry.max_attempts"}
+	}
+
+	switch r.Backoff {
+	case "", FixedBackoff, ExponentialBackoff:
+	default:
+		return fmt.Errorf("invalid backoff '%s' for retry: must be '%s' or '%s'", r.Backoff, FixedBackoff, ExponentialBackoff)
+	}
+
+	if r.MaxDelay != 0 && r.MaxDelay < r.Delay {
+		return fmt.Errorf("retry.max_delay should not be less than retry.delay")
+	}
+
+	return nil
+}
+
+// attempts returns the maximum number of attempts allowed by the policy.
+// A nil policy allows a single attempt.
+func (r *Retry) attempts() uint {
+	if r == nil || r.MaxAttempts == 0 {
+		return 1
+	}
+
+	return r.MaxAttempts
+}
+
+// backoff returns the wait time after the given failed attempt.
+// The wait time is limited by MaxDelay, or by [DefaultMaxDelay] if it is not provided.
+func (r *Retry) backoff(attempt uint) time.Duration {
+	maxDelay := DefaultMaxDelay
+	if r.MaxDelay != 0 {
+		maxDelay = time.Duration(r.MaxDelay) * time.Millisecond
+	}
+
+	delay := min(time.Duration(r.Delay)*time.Millisecond, maxDelay)
+
+	if r.Backoff == ExponentialBackoff {
+		// Doubling stops once the limit is reached, so that the delay does not overflow.
+		for i := uint(1); i < attempt && delay < maxDelay; i++ {
+			delay = min(delay*2, maxDelay)
+		}
+	}
+
+	if r.Jitter && delay > 0 {
+		delay = delay/2 + rand.N(delay/2+1)
+	}
+
+	return delay
+}
+
+// shouldRetry evaluates RetryOn with the variables in the store, along with the response as the res variable.
+func (r *Retry) shouldRetry(context *ExecutionContext, res *http.Response, body any) (bool, error) {
+	vars := context.variables()
+	vars["res"] = newResponse(res, body)
+
+	val, err := expr.Eval(r.RetryOn, vars)
+	if err != nil {
+		return false, fmt.Errorf("invalid expression '%s' for retry_on: %v", r.RetryOn, err)
+	}
+
+	retry, ok := val.(bool)
+	if !ok {
+		return false, fmt.Errorf("evaluating expression '%s' should produce a boolean for retry_on", r.RetryOn)
+	}
+
+	return retry, nil
+}
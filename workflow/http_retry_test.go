@@ -0,0 +1,46 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetry_Backoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		retry   Retry
+		attempt uint
+		want    time.Duration
+	}{
+		{
+			name:    "fixed backoff",
+			retry:   Retry{MaxAttempts: 5, Delay: 200},
+			attempt: 4,
+			want:    200 * time.Millisecond,
+		},
+		{
+			name:    "exponential backoff doubles the delay",
+			retry:   Retry{MaxAttempts: 5, Backoff: ExponentialBackoff, Delay: 200},
+			attempt: 3,
+			want:    800 * time.Millisecond,
+		},
+		{
+			name:    "exponential backoff is limited by max_delay",
+			retry:   Retry{MaxAttempts: 5, Backoff: ExponentialBackoff, Delay: 200, MaxDelay: 500},
+			attempt: 3,
+			want:    500 * time.Millisecond,
+		},
+		{
+			name:    "exponential backoff without max_delay does not overflow",
+			retry:   Retry{MaxAttempts: 200, Backoff: ExponentialBackoff, Delay: 1000},
+			attempt: 199,
+			want:    DefaultMaxDelay,
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.retry.backoff(tt.attempt); got != tt.want {
+			t.Errorf("in test %q; expected backoff to be %s but got %s", tt.name, tt.want, got)
+		}
+	}
+}
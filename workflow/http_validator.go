@@ -13,14 +13,20 @@ type Validator struct {
 }
 
 // Validate validates the http response.
+// The asserts are evaluated with the decoded body as the response variable and the [Response] as the res variable.
 // Returns an error if the validation is falied.
 func (v *Validator) Validate(context *ExecutionContext, statusCode uint, rb *responseBody) error {
+	if v == nil {
+		return nil
+	}
+
 	if v.Status_code != 0 && v.Status_code != statusCode {
 		return fmt.Errorf("status code: expected '%d' but got '%d'", v.Status_code, statusCode)
 	}
 
-	vars := context.store.Map()
+	vars := context.variables()
 	vars["response"] = rb.body
+	vars["res"] = rb.res
 
 	for _, assert := range v.Asserts {
 		err := assert.Validate(vars, rb)
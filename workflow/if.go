@@ -45,25 +45,25 @@ func (i *If) Name() string {
 	return i.StepName
 }
 
+func (i *If) stepLists() []StepList {
+	return []StepList{i.Then, i.Else}
+}
+
 // Execute executes the step with the specified context.
 func (i *If) Execute(context *ExecutionContext) error {
-	variables := context.store.Map()
-
-	if i.cCondition == nil {
-		if i.Condition == "" {
-			return fmt.Errorf("if step '%s': condition is mandatory", i.StepName)
-		}
+	variables := context.variables()
 
-		program, err := expr.Compile(i.Condition, expr.Env(variables), expr.AsBool())
+	if i.Condition == "" {
+		return fmt.Errorf("if step '%s': condition is mandatory", i.StepName)
+	}
 
-		if err != nil {
-			return err
-		}
+	program, err := compileOnce(&i.cCondition, i.Condition, variables, expr.AsBool())
 
-		i.cCondition = program
+	if err != nil {
+		return err
 	}
 
-	output, err := expr.Run(i.cCondition, variables)
+	output, err := expr.Run(program, variables)
 
 	if err != nil {
 		return err
@@ -79,12 +79,8 @@ func (i *If) Execute(context *ExecutionContext) error {
 		executionSteps = i.Else
 	}
 
-	for _, step := range executionSteps {
-		err := step.Execute(context)
-
-		if err != nil {
-			return err
-		}
+	if err := executeSteps(executionSteps, context); err != nil {
+		return err
 	}
 
 	context.logger.Debug("successfully completed the execution of if step '%s'.", i.StepName)
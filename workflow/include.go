@@ -0,0 +1,112 @@
+package workflow
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/expr-lang/expr"
+	"github.com/santhanuv/srotas/internal/store"
+)
+
+// Include represents a step that executes the steps of another configuration file as a sub-workflow.
+// The included configuration runs with its own variables, initialized from Inputs,
+// and the variables defined in its output are stored back into the calling workflow.
+type Include struct {
+	Type       string            // The type of the step.
+	StepName   string            `yaml:"name"` // Identifier for the step.
+	File       string            // Path to the included configuration, relative to the calling configuration.
+	Inputs     map[string]string // Variables of the included configuration mapped to expressions evaluated in the calling workflow.
+	definition *Definition       // Parsed included configuration.
+}
+
+// Validate checks the fields of the [Include] step and returns a list of validation errors, if any.
+func (i *Include) Validate() error {
+	vErr := ValidationError{}
+
+	if i.StepName == "" {
+		vErr.Add(RequiredFieldError{Field: "name"})
+	}
+
+	if i.File == "" {
+		vErr.Add(RequiredFieldError{Field: "file"})
+	}
+
+	if vErr.HasError() {
+		return fmt.Errorf("include step: %w", &vErr)
+	}
+
+	return nil
+}
+
+func (i *Include) Name() string {
+	return i.StepName
+}
+
+// Execute executes the step with the specified context.
+func (i *Include) Execute(context *ExecutionContext) error {
+	def := i.definition
+	if def == nil {
+		return fmt.Errorf("include step '%s': configuration '%s' is not parsed", i.StepName, i.File)
+	}
+
+	vars := context.variables()
+	inputs := make(map[string]any, len(i.Inputs))
+
+	for vn, ve := range i.Inputs {
+		val, err := expr.Eval(ve, vars)
+		if err != nil {
+			return fmt.Errorf("include step '%s': invalid expression '%s' for input '%s': %v", i.StepName, ve, vn, err)
+		}
+
+		inputs[vn] = val
+	}
+
+	iStore := store.NewStore(inputs)
+
+	for vn, ve := range def.Variables {
+		if _, ok := inputs[vn]; ok {
+			return fmt.Errorf("include step '%s': input variable '%s' is already defined", i.StepName, vn)
+		}
+
+		val, err := expr.Eval(ve, inputs)
+		if err != nil {
+			return fmt.Errorf("include step '%s': variable '%s': %v", i.StepName, vn, err)
+		}
+
+		iStore.Set(vn, val)
+	}
+
+	iContext := context.fork(iStore, context.logger.WithTag(filepath.Base(i.File)))
+
+	headers, err := def.Headers.compile(iContext)
+	if err != nil {
+		return fmt.Errorf("include step '%s': %v", i.StepName, err)
+	}
+
+	baseUrl := context.globalOptions.baseUrl
+	if def.BaseUrl != "" {
+		baseUrl = def.BaseUrl
+	}
+
+	iContext.globalOptions = &ConfigOptions{
+		baseUrl: baseUrl,
+		headers: headers,
+	}
+
+	context.logger.Info("executing included configuration '%s' for step '%s'", i.File, i.StepName)
+
+	if err := Execute(def, iContext); err != nil {
+		return fmt.Errorf("include step '%s': %w", i.StepName, err)
+	}
+
+	outputs, err := def.EvalOutput(iStore.Map())
+	if err != nil {
+		return fmt.Errorf("include step '%s': %v", i.StepName, err)
+	}
+
+	context.store.Add(outputs)
+
+	context.logger.Debug("successfully executed include step '%s'", i.StepName)
+
+	return nil
+}
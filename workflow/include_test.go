@@ -0,0 +1,103 @@
+package workflow
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/santhanuv/srotas/internal/log"
+	"github.com/santhanuv/srotas/internal/store"
+)
+
+func writeConfig(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to setup test: unable to write config '%s': %v", name, err)
+	}
+
+	return path
+}
+
+func TestParseConfig_IncludeCycle(t *testing.T) {
+	dir := t.TempDir()
+
+	writeConfig(t, dir, "a.yaml", `
+steps:
+  - type: include
+    step:
+      name: include b
+      file: b.yaml
+`)
+	writeConfig(t, dir, "b.yaml", `
+steps:
+  - type: include
+    step:
+      name: include a
+      file: a.yaml
+`)
+
+	logBuf := bytes.NewBuffer(nil)
+	_, err := ParseConfig(filepath.Join(dir, "a.yaml"), log.New(logBuf, logBuf, logBuf))
+
+	if err == nil {
+		t.Fatalf("expected include cycle error but got none")
+	}
+
+	if !strings.Contains(err.Error(), "include cycle detected") {
+		t.Errorf("expected include cycle error but got %q", err)
+	}
+}
+
+func TestInclude_Execute(t *testing.T) {
+	dir := t.TempDir()
+
+	writeConfig(t, dir, "main.yaml", `
+steps:
+  - type: include
+    step:
+      name: greet
+      file: shared/greet.yaml
+      inputs:
+        name: "user"
+`)
+	if err := os.Mkdir(filepath.Join(dir, "shared"), 0o755); err != nil {
+		t.Fatalf("failed to setup test: %v", err)
+	}
+	writeConfig(t, filepath.Join(dir, "shared"), "greet.yaml", `
+variables:
+  prefix: "'hello '"
+steps: []
+output:
+  greeting: "prefix + name"
+`)
+
+	logBuf := bytes.NewBuffer(nil)
+	logger := log.New(logBuf, logBuf, logBuf)
+
+	def, err := ParseConfig(filepath.Join(dir, "main.yaml"), logger)
+	if err != nil {
+		t.Fatalf("expected no error but got %q", err)
+	}
+
+	s := store.NewStore(map[string]any{"user": "bob"})
+	context, err := NewExecutionContext(WithStore(s), WithLogger(logger), WithGlobalOptions("", nil))
+	if err != nil {
+		t.Fatalf("failed to setup test: unable to create execution context")
+	}
+
+	if err := Execute(def, context); err != nil {
+		t.Fatalf("expected no error but got %q\nLogs: %s", err, logBuf.String())
+	}
+
+	if greeting, _ := s.Get("greeting"); greeting != "hello bob" {
+		t.Errorf("expected 'greeting' to be %q but got '%v'", "hello bob", greeting)
+	}
+
+	if _, ok := s.Get("prefix"); ok {
+		t.Errorf("expected variables of the included configuration not to be stored")
+	}
+}
@@ -0,0 +1,228 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+)
+
+// loopControl is the control-flow signal returned by [Break] and [Continue] steps.
+// It unwinds the execution to the nearest enclosing loop and is not reported as a failure by loops.
+type loopControl struct {
+	stop bool   // Stops the loop if true; otherwise skips to the next iteration.
+	step string // Name of the step that raised the signal.
+}
+
+func (l *loopControl) Error() string {
+	stepType := "continue"
+	if l.stop {
+		stepType = "break"
+	}
+
+	return fmt.Sprintf("%s step '%s' is used outside of a loop", stepType, l.step)
+}
+
+// Break represents a step that stops the nearest enclosing loop.
+// Combined with the when guard of steps, the loop is stopped only if a condition is met.
+type Break struct {
+	Type     string // The type of the step.
+	StepName string `yaml:"name"` // Identifier for the step.
+}
+
+// Validate checks the fields of the [Break] step and returns a list of validation errors, if any.
+func (b *Break) Validate() error {
+	if b.StepName == "" {
+		vErr := ValidationError{}
+		vErr.Add(RequiredFieldError{Field: "name"})
+
+		return fmt.Errorf("break step: %w", &vErr)
+	}
+
+	return nil
+}
+
+func (b *Break) Name() string {
+	return b.StepName
+}
+
+// Execute executes the step with the specified context.
+func (b *Break) Execute(context *ExecutionContext) error {
+	context.logger.Debug("break step '%s' stopped the loop", b.StepName)
+
+	return &loopControl{stop: true, step: b.StepName}
+}
+
+// Continue represents a step that skips the remaining steps of the current iteration of the nearest enclosing loop.
+// Combined with the when guard of steps, the iteration is skipped only if a condition is met.
+type Continue struct {
+	Type     string // The type of the step.
+	StepName string `yaml:"name"` // Identifier for the step.
+}
+
+// Validate checks the fields of the [Continue] step and returns a list of validation errors, if any.
+func (c *Continue) Validate() error {
+	if c.StepName == "" {
+		vErr := ValidationError{}
+		vErr.Add(RequiredFieldError{Field: "name"})
+
+		return fmt.Errorf("continue step: %w", &vErr)
+	}
+
+	return nil
+}
+
+func (c *Continue) Name() string {
+	return c.StepName
+}
+
+// Execute executes the step with the specified context.
+func (c *Continue) Execute(context *ExecutionContext) error {
+	context.logger.Debug("continue step '%s' skipped the iteration", c.StepName)
+
+	return &loopControl{stop: false, step: c.StepName}
+}
+
+// evalWhen evaluates the when expression with the variables in the store, compiling it into program on first use.
+// An empty expression evaluates to true.
+func evalWhen(when string, program **vm.Program, context *ExecutionContext) (bool, error) {
+	if when == "" {
+		return true, nil
+	}
+
+	variables := context.variables()
+
+	p, err := compileOnce(program, when, variables, expr.AsBool())
+
+	if err != nil {
+		return false, err
+	}
+
+	output, err := expr.Run(p, variables)
+
+	if err != nil {
+		return false, err
+	}
+
+	return output.(bool), nil
+}
+
+// executeLoopBody executes the steps of a single loop iteration.
+// It reports whether the loop should stop because of a [Break] step.
+// An error is returned without executing the steps if the execution is interrupted.
+func executeLoopBody(steps StepList, context *ExecutionContext) (bool, error) {
+	if reason := context.Interrupted(); reason != nil {
+		return true, fmt.Errorf("%w during loop iteration", reason)
+	}
+
+	err := executeSteps(steps, context)
+
+	var lc *loopControl
+	if errors.As(err, &lc) {
+		return lc.stop, nil
+	}
+
+	return false, err
+}
+
+// checkLoopControl returns an error if a [Break] or [Continue] step in steps is not within a loop.
+// Steps of a parallel branch cannot control a loop outside of the parallel step.
+func checkLoopControl(steps StepList, inLoop bool) error {
+	for _, step := range steps {
+		step = unwrapStep(step)
+
+		var stepType string
+
+		switch step.(type) {
+		case *Break:
+			stepType = "break"
+		case *Continue:
+			stepType = "continue"
+		}
+
+		if stepType != "" && !inLoop {
+			vErr := ValidationError{}
+			vErr.Add(fmt.Errorf("step '%s' should be within a while or forEach step", step.Name()))
+
+			return fmt.Errorf("%s step: %w", stepType, &vErr)
+		}
+
+		container, ok := step.(stepContainer)
+		if !ok {
+			continue
+		}
+
+		nestedInLoop := inLoop
+
+		switch step.(type) {
+		case *While, *ForEach:
+			nestedInLoop = true
+		case *Parallel:
+			nestedInLoop = false
+		}
+
+		for _, nested := range container.stepLists() {
+			if err := checkLoopControl(nested, nestedInLoop); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// loopLimit tracks the iterations and the elapsed time of a loop step against its limits.
+type loopLimit struct {
+	step          string        // Name of the loop step.
+	maxIterations uint          // Maximum number of iterations allowed.
+	maxDuration   time.Duration // Maximum duration allowed, zero means no limit.
+	start         time.Time     // Time the loop started.
+	iterations    uint          // Number of iterations started.
+}
+
+// newLoopLimit returns a [loopLimit] for the loop step, starting now.
+// If maxIterations is zero, the maximum number of iterations of the context is used.
+// The maxDuration is in milliseconds.
+func newLoopLimit(context *ExecutionContext, step string, maxIterations uint, maxDuration uint) *loopLimit {
+	if maxIterations == 0 {
+		maxIterations = context.maxIterations
+	}
+
+	return &loopLimit{
+		step:          step,
+		maxIterations: maxIterations,
+		maxDuration:   time.Duration(maxDuration) * time.Millisecond,
+		start:         time.Now(),
+	}
+}
+
+// next records the start of an iteration.
+// It returns a [LoopLimitError] with the values of the loop variables if the iteration exceeds the limits.
+func (l *loopLimit) next(variables map[string]any) error {
+	elapsed := time.Since(l.start)
+
+	switch {
+	case l.iterations >= l.maxIterations:
+		return &LoopLimitError{
+			Step:          l.step,
+			Iterations:    l.iterations,
+			Elapsed:       elapsed,
+			MaxIterations: l.maxIterations,
+			Variables:     variables,
+		}
+	case l.maxDuration != 0 && elapsed > l.maxDuration:
+		return &LoopLimitError{
+			Step:        l.step,
+			Iterations:  l.iterations,
+			Elapsed:     elapsed,
+			MaxDuration: l.maxDuration,
+			Variables:   variables,
+		}
+	}
+
+	l.iterations++
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package workflow
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/santhanuv/srotas/internal/log"
+	"github.com/santhanuv/srotas/internal/store"
+)
+
+func TestParseConfig_LoopControlOutsideLoop(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		err    bool
+	}{
+		{
+			name: "break at top level",
+			config: `
+steps:
+  - type: break
+    step:
+      name: stop
+`,
+			err: true,
+		},
+		{
+			name: "continue within if outside loop",
+			config: `
+steps:
+  - type: if
+    step:
+      name: check
+      condition: "true"
+      then:
+        - type: continue
+          step:
+            name: skip
+`,
+			err: true,
+		},
+		{
+			name: "break within parallel branch inside loop",
+			config: `
+steps:
+  - type: while
+    step:
+      name: loop
+      condition: "false"
+      body:
+        - type: parallel
+          step:
+            name: parallel
+            branches:
+              - name: branch
+                steps:
+                  - type: break
+                    step:
+                      name: stop
+`,
+			err: true,
+		},
+		{
+			name: "break within if inside loop",
+			config: `
+steps:
+  - type: forEach
+    step:
+      name: loop
+      list: "[1]"
+      as: item
+      body:
+        - type: if
+          step:
+            name: check
+            condition: "item == 1"
+            then:
+              - type: break
+                step:
+                  name: stop
+`,
+			err: false,
+		},
+	}
+
+	for _, tt := range tests {
+		path := writeConfig(t, t.TempDir(), "config.yaml", tt.config)
+
+		logBuf := bytes.NewBuffer(nil)
+		_, err := ParseConfig(path, log.New(logBuf, logBuf, logBuf))
+
+		if tt.err && (err == nil || !strings.Contains(err.Error(), "validation errors")) {
+			t.Errorf("in test %q; expected validation error but got %v", tt.name, err)
+		}
+
+		if !tt.err && err != nil {
+			t.Errorf("in test %q; expected no error but got %q", tt.name, err)
+		}
+	}
+}
+
+func TestLoopControl_ForEach(t *testing.T) {
+	forEach := ForEach{
+		Type:     "forEach",
+		StepName: "loop",
+		List:     "[1, 2, 3, 4]",
+		As:       "item",
+		Body: StepList{
+			&guardedStep{Step: &Continue{Type: "continue", StepName: "skip"}, stepType: "continue", when: "item == 2"},
+			&guardedStep{Step: &Break{Type: "break", StepName: "stop"}, stepType: "break", when: "item == 4"},
+			&Set{Type: "set", StepName: "collect", Variables: map[string]string{"seen": "concat(seen, [item])"}},
+		},
+	}
+
+	s := store.NewStore(map[string]any{"seen": []any{}})
+	logBuf := bytes.NewBuffer(nil)
+
+	context, err := NewExecutionContext(WithStore(s), WithLogger(log.New(logBuf, logBuf, logBuf)))
+	if err != nil {
+		t.Fatalf("failed to setup test: unable to create execution context")
+	}
+
+	if err := forEach.Execute(context); err != nil {
+		t.Fatalf("expected no error but got %q", err)
+	}
+
+	want := []any{1, 3}
+	if seen, _ := s.Get("seen"); !reflect.DeepEqual(seen, want) {
+		t.Errorf("expected 'seen' to be %v but got %v", want, seen)
+	}
+}
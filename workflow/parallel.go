@@ -0,0 +1,184 @@
+package workflow
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Parallel represents a step that executes the steps of each branch concurrently.
+// Each branch runs against an isolated copy of the store. Once all branches complete,
+// the variables set or removed by each branch that succeeded are merged back in the order the branches are declared.
+type Parallel struct {
+	Type     string   // The type of the step.
+	StepName string   `yaml:"name"` // Identifier for the step.
+	Branches []Branch // Named branches executed concurrently.
+}
+
+// Branch represents a named sequence of steps executed within a [Parallel] step.
+type Branch struct {
+	Name  string   // Identifier for the branch.
+	Steps StepList // Steps to execute in the branch.
+}
+
+// branchResult holds the outcome of executing a single [Branch].
+type branchResult struct {
+	set     map[string]any // Variables created or modified by the branch.
+	removed []string       // Variables removed by the branch.
+	err     error          // Error returned by the branch, if any.
+}
+
+// Validate checks the fields of the [Parallel] step and returns a list of validation errors, if any.
+func (p *Parallel) Validate() error {
+	vErr := ValidationError{}
+
+	if p.StepName == "" {
+		vErr.Add(RequiredFieldError{Field: "name"})
+	}
+
+	if len(p.Branches) == 0 {
+		vErr.Add(RequiredFieldError{Field: "branches"})
+	}
+
+	names := make(map[string]bool, len(p.Branches))
+	for idx, branch := range p.Branches {
+		if branch.Name == "" {
+			vErr.Add(RequiredFieldError{Field: fmt.Sprintf("branches[%d].name", idx)})
+		} else if names[branch.Name] {
+			vErr.Add(fmt.Errorf("branch '%s' is defined more than once", branch.Name))
+		}
+
+		names[branch.Name] = true
+
+		if branch.Steps == nil {
+			vErr.Add(RequiredFieldError{Field: fmt.Sprintf("branches[%d].steps", idx)})
+		}
+	}
+
+	if vErr.HasError() {
+		return fmt.Errorf("parallel step: %w", &vErr)
+	}
+
+	return nil
+}
+
+func (p *Parallel) Name() string {
+	return p.StepName
+}
+
+func (p *Parallel) stepLists() []StepList {
+	lists := make([]StepList, 0, len(p.Branches))
+	for _, branch := range p.Branches {
+		lists = append(lists, branch.Steps)
+	}
+
+	return lists
+}
+
+// Execute executes the step with the specified context.
+func (p *Parallel) Execute(context *ExecutionContext) error {
+	base := context.store.Map()
+	results := make([]branchResult, len(p.Branches))
+
+	context.logger.Info("starting %d branches of parallel step '%s'", len(p.Branches), p.StepName)
+
+	var wg sync.WaitGroup
+	for idx, branch := range p.Branches {
+		wg.Add(1)
+
+		// Each branch gets a deep copy, so that maps and lists are not shared between branches.
+		bStore := context.store.Clone()
+
+		go func() {
+			defer wg.Done()
+
+			bContext := context.fork(bStore, context.logger.WithTag(branch.Name))
+
+			err := executeBranch(branch, bContext)
+			set, removed := diffVariables(base, bStore.Map())
+
+			results[idx] = branchResult{
+				set:     set,
+				removed: removed,
+				err:     err,
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	var errs []string
+	owners := map[string]string{}
+
+	for idx, result := range results {
+		branch := p.Branches[idx].Name
+
+		// The variables of a failed branch are discarded.
+		if result.err != nil {
+			errs = append(errs, fmt.Sprintf("branch '%s': %v", branch, result.err))
+			continue
+		}
+
+		for name, val := range result.set {
+			if owner, ok := owners[name]; ok {
+				errs = append(errs, fmt.Sprintf("variable '%s' is modified by both branch '%s' and branch '%s'", name, owner, branch))
+				continue
+			}
+
+			owners[name] = branch
+			context.store.Set(name, val)
+		}
+
+		for _, name := range result.removed {
+			if owner, ok := owners[name]; ok {
+				errs = append(errs, fmt.Sprintf("variable '%s' is modified by both branch '%s' and branch '%s'", name, owner, branch))
+				continue
+			}
+
+			owners[name] = branch
+			context.store.Remove(name)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("parallel step '%s' failed:\n\t%s", p.StepName, strings.Join(errs, "\n\t"))
+	}
+
+	context.logger.Debug("successfully executed parallel step '%s'", p.StepName)
+
+	return nil
+}
+
+// executeBranch executes the steps of the branch sequentially with the specified context.
+func executeBranch(branch Branch, context *ExecutionContext) error {
+	if err := executeSteps(branch.Steps, context); err != nil {
+		return err
+	}
+
+	context.logger.Debug("successfully executed branch '%s'", branch.Name)
+
+	return nil
+}
+
+// diffVariables compares the variables after executing a branch with the base variables.
+// It returns the variables that were created or modified, and the names of the variables that were removed.
+func diffVariables(base, current map[string]any) (map[string]any, []string) {
+	set := map[string]any{}
+
+	for name, val := range current {
+		if bVal, ok := base[name]; !ok || !reflect.DeepEqual(bVal, val) {
+			set[name] = val
+		}
+	}
+
+	var removed []string
+
+	for name := range base {
+		if _, ok := current[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+
+	return set, removed
+}
@@ -0,0 +1,219 @@
+package workflow
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/santhanuv/srotas/internal/log"
+	"github.com/santhanuv/srotas/internal/store"
+)
+
+func TestParallel_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		parallel Parallel
+		err      bool
+	}{
+		{
+			name: "'name' is not provided",
+			parallel: Parallel{
+				Type:     "parallel",
+				Branches: []Branch{{Name: "branch", Steps: StepList{}}},
+			},
+			err: true,
+		},
+		{
+			name: "'branches' field is not provided",
+			parallel: Parallel{
+				Type:     "parallel",
+				StepName: "name",
+			},
+			err: true,
+		},
+		{
+			name: "branch name is not provided",
+			parallel: Parallel{
+				Type:     "parallel",
+				StepName: "name",
+				Branches: []Branch{{Steps: StepList{}}},
+			},
+			err: true,
+		},
+		{
+			name: "branch steps are not provided",
+			parallel: Parallel{
+				Type:     "parallel",
+				StepName: "name",
+				Branches: []Branch{{Name: "branch"}},
+			},
+			err: true,
+		},
+		{
+			name: "duplicate branch names",
+			parallel: Parallel{
+				Type:     "parallel",
+				StepName: "name",
+				Branches: []Branch{
+					{Name: "branch", Steps: StepList{}},
+					{Name: "branch", Steps: StepList{}},
+				},
+			},
+			err: true,
+		},
+		{
+			name: "All required fields are provided",
+			parallel: Parallel{
+				Type:     "parallel",
+				StepName: "name",
+				Branches: []Branch{{Name: "branch", Steps: StepList{}}},
+			},
+			err: false,
+		},
+	}
+
+	for _, tt := range tests {
+		err := tt.parallel.Validate()
+
+		if !tt.err && err != nil {
+			t.Errorf("in test %q; expected no error but got %q", tt.name, err)
+			continue
+		}
+
+		if tt.err {
+			if err == nil {
+				t.Errorf("in test %q; expected error but got none", tt.name)
+				continue
+			}
+
+			var target *ValidationError
+			if !errors.As(err, &target) {
+				t.Errorf("in test %q; got %q; want validation error", tt.name, err)
+			}
+		}
+	}
+}
+
+// storeStep is a test step that stores the given variables when executed.
+type storeStep struct {
+	vars map[string]any
+	err  error
+}
+
+func (s *storeStep) Execute(context *ExecutionContext) error {
+	context.store.Add(s.vars)
+	return s.err
+}
+
+func (s *storeStep) Validate() error { return nil }
+
+func (s *storeStep) Name() string { return "store" }
+
+// mutateStep is a test step that modifies a key of a map variable in place when executed.
+type mutateStep struct {
+	variable string
+	key      string
+	val      any
+	err      error
+}
+
+func (m *mutateStep) Execute(context *ExecutionContext) error {
+	v, _ := context.store.Get(m.variable)
+	v.(map[string]any)[m.key] = m.val
+
+	return m.err
+}
+
+func (m *mutateStep) Validate() error { return nil }
+
+func (m *mutateStep) Name() string { return "mutate" }
+
+func TestParallel_Execute(t *testing.T) {
+	tests := []struct {
+		name     string
+		branches []Branch
+		want     map[string]any
+		err      bool
+	}{
+		{
+			name: "variables from all branches are merged",
+			branches: []Branch{
+				{Name: "users", Steps: StepList{&storeStep{vars: map[string]any{"user": 1}}}},
+				{Name: "products", Steps: StepList{&storeStep{vars: map[string]any{"product": 2}}}},
+			},
+			want: map[string]any{"base": 0, "user": 1, "product": 2},
+		},
+		{
+			name: "variable modified by multiple branches",
+			branches: []Branch{
+				{Name: "first", Steps: StepList{&storeStep{vars: map[string]any{"base": 1}}}},
+				{Name: "second", Steps: StepList{&storeStep{vars: map[string]any{"base": 2}}}},
+			},
+			want: map[string]any{"base": 1},
+			err:  true,
+		},
+		{
+			name: "failing branch does not discard other branches",
+			branches: []Branch{
+				{Name: "ok", Steps: StepList{&storeStep{vars: map[string]any{"user": 1}}}},
+				{Name: "failing", Steps: StepList{&storeStep{vars: map[string]any{"partial": 2}, err: errors.New("failed")}}},
+			},
+			want: map[string]any{"base": 0, "user": 1, "partial": nil},
+			err:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		s := store.NewStore(map[string]any{"base": 0})
+		logBuf := bytes.NewBuffer(nil)
+
+		context, err := NewExecutionContext(WithStore(s), WithLogger(log.New(logBuf, logBuf, logBuf)))
+		if err != nil {
+			t.Fatalf("in test %q; failed to setup test: %v", tt.name, err)
+		}
+
+		p := Parallel{Type: "parallel", StepName: "parallel", Branches: tt.branches}
+		err = p.Execute(context)
+
+		if tt.err && err == nil {
+			t.Errorf("in test %q; expected error but got none", tt.name)
+		}
+
+		if !tt.err && err != nil {
+			t.Errorf("in test %q; expected no error but got %q", tt.name, err)
+		}
+
+		for name, want := range tt.want {
+			if got, _ := s.Get(name); got != want {
+				t.Errorf("in test %q; expected variable '%s' to be '%v' but got '%v'", tt.name, name, want, got)
+			}
+		}
+	}
+}
+
+func TestParallel_ExecuteIsolatesNestedValues(t *testing.T) {
+	config := map[string]any{"retries": 1, "hosts": []any{map[string]any{"name": "a"}}}
+	s := store.NewStore(map[string]any{"config": config})
+	logBuf := bytes.NewBuffer(nil)
+
+	context, err := NewExecutionContext(WithStore(s), WithLogger(log.New(logBuf, logBuf, logBuf)))
+	if err != nil {
+		t.Fatalf("failed to setup test: %v", err)
+	}
+
+	p := Parallel{
+		Type:     "parallel",
+		StepName: "parallel",
+		Branches: []Branch{
+			{Name: "failing", Steps: StepList{&mutateStep{variable: "config", key: "retries", val: 5, err: errors.New("failed")}}},
+		},
+	}
+
+	if err := p.Execute(context); err == nil {
+		t.Fatalf("expected error but got none")
+	}
+
+	if got := config["retries"]; got != 1 {
+		t.Errorf("expected the variables of the parent not to be modified by the branch but got retries '%v'", got)
+	}
+}
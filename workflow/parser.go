@@ -4,19 +4,99 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/santhanuv/srotas/internal/log"
 	"gopkg.in/yaml.v3"
 )
 
 // ParseConfig reads the configuration file from the given path and returns a [Definition] representing its contents.
+// Configurations referenced by include steps are parsed as well, and an error is returned if they include each other in a cycle.
 func ParseConfig(path string, logger *log.Logger) (*Definition, error) {
+	return parseConfig(path, logger, nil)
+}
+
+// parseConfig parses the configuration file at path.
+// The including stack holds the absolute paths of the configurations including the one being parsed.
+func parseConfig(path string, logger *log.Logger, including []string) (*Definition, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
 	cfg, err := os.ReadFile(path)
 
 	if err != nil {
 		return nil, err
 	}
 
+	def, err := unmarshalConfig(path, cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, function := range def.Functions {
+		if err := function.Validate(); err != nil {
+			return nil, fmt.Errorf("function '%s': %w", name, err)
+		}
+	}
+
+	if err := checkFunctionCycles(def.Functions); err != nil {
+		return nil, err
+	}
+
+	including = append(including, path)
+
+	for _, steps := range def.stepLists() {
+		if err := checkLoopControl(steps, false); err != nil {
+			return nil, err
+		}
+
+		err = walkSteps(steps, func(step Step) error {
+			switch s := step.(type) {
+			case *Include:
+				return resolveInclude(s, logger, including)
+			case *Call:
+				return s.bind(def.Functions)
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return def, nil
+}
+
+// resolveInclude parses the configuration included by the step.
+// An error is returned if the included configuration is already being parsed in the including stack.
+func resolveInclude(include *Include, logger *log.Logger, including []string) error {
+	if slices.Contains(including, include.File) {
+		cycle := append(slices.Clone(including), include.File)
+		return fmt.Errorf("include step '%s': include cycle detected: %s", include.StepName, strings.Join(cycle, " -> "))
+	}
+
+	iDef, err := parseConfig(include.File, logger, including)
+	if err != nil {
+		return fmt.Errorf("include step '%s': failed to parse '%s': %w", include.StepName, include.File, err)
+	}
+
+	if err := iDef.Validate(); err != nil {
+		return fmt.Errorf("include step '%s': invalid configuration '%s': %w", include.StepName, include.File, err)
+	}
+
+	include.definition = iDef
+
+	return nil
+}
+
+// unmarshalConfig unmarshals cfg into a [Definition].
+// Relative paths within the configuration are resolved against the directory of the configuration at path.
+func unmarshalConfig(path string, cfg []byte, logger *log.Logger) (*Definition, error) {
 	wd, err := os.Getwd()
 	if err != nil {
 		return nil, err
@@ -53,20 +133,35 @@ type stepParserFunc func(node *yaml.Node) (Step, error)
 type stepParser map[string]stepParserFunc
 
 // parse parses the node based on the stepType and returns the corresponding step.
+// Step types registered with [RegisterStep] are parsed if stepType is not a built-in step type.
+// If the node defines the when field, the step is guarded so that it is executed only if the expression evaluates to true.
 // It returns the parsed Step and an error if parsing fails.
 func (sp stepParser) parse(stepType string, node *yaml.Node) (Step, error) {
 	parser, ok := sp[stepType]
 
+	if !ok {
+		parser, ok = registeredStepParser(stepType)
+	}
+
 	if !ok {
 		return nil, fmt.Errorf("unsupported type %s for step", stepType)
 	}
 
+	when, err := extractWhen(node)
+	if err != nil {
+		return nil, fmt.Errorf("%s step: %v", stepType, err)
+	}
+
 	step, err := parser(node)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if when != "" {
+		step = &guardedStep{Step: step, stepType: stepType, when: when}
+	}
+
 	return step, err
 }
 
@@ -115,6 +210,192 @@ func newStepParser() stepParser {
 				return nil, err
 			}
 
+			return step, nil
+		},
+		"parallel": func(node *yaml.Node) (Step, error) {
+			step := &Parallel{
+				Type: "parallel",
+			}
+
+			if err := parseStep(step, node); err != nil {
+				return nil, err
+			}
+
+			return step, nil
+		},
+		"poll": func(node *yaml.Node) (Step, error) {
+			step := &Poll{
+				Type: "poll",
+			}
+
+			if err := parseStep(step, node); err != nil {
+				return nil, err
+			}
+
+			return step, nil
+		},
+		"include": func(node *yaml.Node) (Step, error) {
+			step := &Include{
+				Type: "include",
+			}
+
+			if err := parseStep(step, node); err != nil {
+				return nil, err
+			}
+
+			file, err := filepath.Abs(step.File)
+			if err != nil {
+				return nil, fmt.Errorf("include step '%s': %v", step.StepName, err)
+			}
+
+			step.File = file
+
+			return step, nil
+		},
+		"call": func(node *yaml.Node) (Step, error) {
+			step := &Call{
+				Type: "call",
+			}
+
+			if err := parseStep(step, node); err != nil {
+				return nil, err
+			}
+
+			return step, nil
+		},
+		"set": func(node *yaml.Node) (Step, error) {
+			step := &Set{
+				Type: "set",
+			}
+
+			if err := parseStep(step, node); err != nil {
+				return nil, err
+			}
+
+			return step, nil
+		},
+		"switch": func(node *yaml.Node) (Step, error) {
+			step := &Switch{
+				Type: "switch",
+			}
+
+			if err := parseStep(step, node); err != nil {
+				return nil, err
+			}
+
+			return step, nil
+		},
+		"break": func(node *yaml.Node) (Step, error) {
+			step := &Break{
+				Type: "break",
+			}
+
+			if err := parseStep(step, node); err != nil {
+				return nil, err
+			}
+
+			return step, nil
+		},
+		"continue": func(node *yaml.Node) (Step, error) {
+			step := &Continue{
+				Type: "continue",
+			}
+
+			if err := parseStep(step, node); err != nil {
+				return nil, err
+			}
+
+			return step, nil
+		},
+		"try": func(node *yaml.Node) (Step, error) {
+			step := &Try{
+				Type: "try",
+			}
+
+			if err := parseStep(step, node); err != nil {
+				return nil, err
+			}
+
+			return step, nil
+		},
+		"assert": func(node *yaml.Node) (Step, error) {
+			step := &AssertStep{
+				Type: "assert",
+			}
+
+			if err := parseStep(step, node); err != nil {
+				return nil, err
+			}
+
+			return step, nil
+		},
+		"prompt": func(node *yaml.Node) (Step, error) {
+			step := &Prompt{
+				Type: "prompt",
+			}
+
+			if err := parseStep(step, node); err != nil {
+				return nil, err
+			}
+
+			return step, nil
+		},
+		"exec": func(node *yaml.Node) (Step, error) {
+			step := &Exec{
+				Type: "exec",
+			}
+
+			if err := parseStep(step, node); err != nil {
+				return nil, err
+			}
+
+			dir, err := filepath.Abs(step.Dir)
+			if err != nil {
+				return nil, fmt.Errorf("exec step '%s': %v", step.StepName, err)
+			}
+
+			step.Dir = dir
+
+			return step, nil
+		},
+		"write_file": func(node *yaml.Node) (Step, error) {
+			step := &WriteFile{
+				Type: "write_file",
+			}
+
+			if err := parseStep(step, node); err != nil {
+				return nil, err
+			}
+
+			if step.Path != "" {
+				path, err := filepath.Abs(step.Path)
+				if err != nil {
+					return nil, fmt.Errorf("write_file step '%s': %v", step.StepName, err)
+				}
+
+				step.Path = path
+			}
+
+			return step, nil
+		},
+		"read_file": func(node *yaml.Node) (Step, error) {
+			step := &ReadFile{
+				Type: "read_file",
+			}
+
+			if err := parseStep(step, node); err != nil {
+				return nil, err
+			}
+
+			if step.Path != "" {
+				path, err := filepath.Abs(step.Path)
+				if err != nil {
+					return nil, fmt.Errorf("read_file step '%s': %v", step.StepName, err)
+				}
+
+				step.Path = path
+			}
+
 			return step, nil
 		},
 	}
@@ -0,0 +1,205 @@
+package workflow
+
+import (
+	ctx "context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+)
+
+// Poll represents a step that repeatedly sends an HTTP request until the Until condition evaluates to true.
+// Polling stops with an error once MaxAttempts or Timeout is reached.
+// The validations and store of the request are applied to the final response.
+type Poll struct {
+	Type        string      // The type of the step.
+	StepName    string      `yaml:"name"` // Identifier for the step.
+	Request     *Request    // HTTP request sent in each attempt.
+	Until       string      // Expression evaluated on each response that stops polling when true.
+	cUntil      *vm.Program // Precompiled until expression.
+	Interval    uint        // Wait time (milliseconds) between attempts.
+	Timeout     uint        // Maximum time (milliseconds) allowed for polling.
+	MaxAttempts uint        `yaml:"max_attempts"` // Maximum number of requests sent.
+}
+
+// Validate checks the fields of the [Poll] step and returns a list of validation errors, if any.
+func (p *Poll) Validate() error {
+	vErr := ValidationError{}
+
+	if p.StepName == "" {
+		vErr.Add(RequiredFieldError{Field: "name"})
+	}
+
+	if p.Until == "" {
+		vErr.Add(RequiredFieldError{Field: "until"})
+	}
+
+	if p.Timeout == 0 && p.MaxAttempts == 0 {
+		vErr.Add(fmt.Errorf("either 'timeout' or 'max_attempts' should be provided"))
+	}
+
+	if p.Request == nil {
+		vErr.Add(RequiredFieldError{Field: "request"})
+	} else {
+		if p.Request.Retry != nil || p.Request.Delay != 0 {
+			vErr.Add(fmt.Errorf("'retry' and 'delay' are not supported for the request of a poll step, use 'interval' instead"))
+		}
+
+		if err := p.request().Validate(); err != nil {
+			vErr.Add(err)
+		}
+	}
+
+	if vErr.HasError() {
+		return fmt.Errorf("poll step: %w", &vErr)
+	}
+
+	return nil
+}
+
+func (p *Poll) Name() string {
+	return p.StepName
+}
+
+// request returns the request sent in each attempt.
+// The request is identified by the poll step unless it is named explicitly.
+func (p *Poll) request() *Request {
+	if p.Request.StepName != "" {
+		return p.Request
+	}
+
+	r := *p.Request
+	r.StepName = p.StepName
+
+	return &r
+}
+
+// Execute executes the step with the specified context.
+func (p *Poll) Execute(context *ExecutionContext) error {
+	r := p.request()
+
+	req, err := r.build(context)
+	if err != nil {
+		return fmt.Errorf("failed executing poll step '%s': %v", p.StepName, err)
+	}
+
+	saved := saveResponse(context)
+	var stored map[string]string
+	defer func() { saved.restore(context, stored) }()
+
+	start := time.Now()
+	interval := time.Duration(p.Interval) * time.Millisecond
+	timeout := time.Duration(p.Timeout) * time.Millisecond
+
+	// Each request is bounded by the time remaining before the timeout.
+	pollCtx := context.ctx
+	errTimeout := fmt.Errorf("timed out after %s waiting for condition '%s'", timeout, p.Until)
+
+	if timeout != 0 {
+		var cancel ctx.CancelFunc
+		pollCtx, cancel = ctx.WithDeadlineCause(context.ctx, start.Add(timeout), errTimeout)
+		defer cancel()
+	}
+
+	var last *Response
+
+	for attempt := uint(1); ; attempt++ {
+		context.logger.Info("polling '%s' (attempt %d): %s %s", p.StepName, attempt, req.Method, req.Url)
+
+		res, body, parseErr, err := r.send(context, pollCtx, req)
+		if err != nil {
+			if context.Interrupted() == nil && errors.Is(ctx.Cause(pollCtx), errTimeout) {
+				return p.timeoutError(errTimeout, attempt, last)
+			}
+
+			return fmt.Errorf("poll step '%s': %w", p.StepName, err)
+		}
+
+		if parseErr != nil {
+			return parseErr
+		}
+
+		resBody := responseBody{body: body, res: newResponse(res, body)}
+		setResponse(context, &resBody)
+		last = resBody.res
+
+		done, err := p.evalUntil(context)
+		if err != nil {
+			return fmt.Errorf("poll step '%s': %v", p.StepName, err)
+		}
+
+		if done {
+			context.logger.Debug("poll step '%s' completed after %d attempt(s)", p.StepName, attempt)
+
+			if err := r.Validations.Validate(context, res.StatusCode, &resBody); err != nil {
+				return responseError(r.StepName, resBody.res, err)
+			}
+
+			if err := resBody.store(r.Store, context); err != nil {
+				return fmt.Errorf("failed executing poll step '%s': %v", p.StepName, err)
+			}
+
+			stored = r.Store
+
+			return nil
+		}
+
+		elapsed := time.Since(start)
+
+		if p.MaxAttempts != 0 && attempt >= p.MaxAttempts {
+			err := fmt.Errorf("condition '%s' not met after %d attempt(s) in %s", p.Until, attempt, elapsed.Round(time.Millisecond))
+			return responseError(r.StepName, resBody.res, err)
+		}
+
+		wait := interval
+
+		if timeout != 0 {
+			if elapsed >= timeout {
+				return p.timeoutError(errTimeout, attempt, last)
+			}
+
+			wait = min(wait, timeout-elapsed)
+		}
+
+		if err := context.sleep(wait); err != nil {
+			return fmt.Errorf("poll step '%s' aborted after %d attempt(s): %w", p.StepName, attempt, err)
+		}
+
+		if timeout != 0 && time.Since(start) >= timeout {
+			return p.timeoutError(errTimeout, attempt, last)
+		}
+	}
+}
+
+// timeoutError returns the error for a poll that timed out after the given number of attempts,
+// including the last response if there is one.
+func (p *Poll) timeoutError(err error, attempts uint, last *Response) error {
+	err = fmt.Errorf("%v (%d attempt(s))", err, attempts)
+
+	if last == nil {
+		return fmt.Errorf("poll step '%s': %v", p.StepName, err)
+	}
+
+	return responseError(p.request().StepName, last, err)
+}
+
+// evalUntil evaluates the until condition with the variables in the store.
+func (p *Poll) evalUntil(context *ExecutionContext) (bool, error) {
+	variables := context.variables()
+
+	program, err := compileOnce(&p.cUntil, p.Until, variables, expr.AsBool())
+
+	if err != nil {
+		return false, err
+	}
+
+	output, err := expr.Run(program, variables)
+
+	if err != nil {
+		return false, err
+	}
+
+	return output.(bool), nil
+}
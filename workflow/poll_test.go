@@ -0,0 +1,239 @@
+package workflow_test
+
+import (
+	"bytes"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/santhanuv/srotas/internal/http"
+	"github.com/santhanuv/srotas/internal/log"
+	"github.com/santhanuv/srotas/internal/store"
+	"github.com/santhanuv/srotas/workflow"
+)
+
+func TestPoll_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		poll workflow.Poll
+		err  bool
+	}{
+		{
+			name: "'name' is not provided",
+			poll: workflow.Poll{
+				Type:        "poll",
+				Until:       "response.done",
+				MaxAttempts: 3,
+				Request:     &workflow.Request{Url: "url", Method: "GET"},
+			},
+			err: true,
+		},
+		{
+			name: "'until' is not provided",
+			poll: workflow.Poll{
+				Type:        "poll",
+				StepName:    "name",
+				MaxAttempts: 3,
+				Request:     &workflow.Request{Url: "url", Method: "GET"},
+			},
+			err: true,
+		},
+		{
+			name: "neither 'timeout' nor 'max_attempts' is provided",
+			poll: workflow.Poll{
+				Type:     "poll",
+				StepName: "name",
+				Until:    "response.done",
+				Request:  &workflow.Request{Url: "url", Method: "GET"},
+			},
+			err: true,
+		},
+		{
+			name: "'request' is not provided",
+			poll: workflow.Poll{
+				Type:        "poll",
+				StepName:    "name",
+				Until:       "response.done",
+				MaxAttempts: 3,
+			},
+			err: true,
+		},
+		{
+			name: "request has a retry policy",
+			poll: workflow.Poll{
+				Type:        "poll",
+				StepName:    "name",
+				Until:       "response.done",
+				MaxAttempts: 3,
+				Request:     &workflow.Request{Url: "url", Method: "GET", Retry: &workflow.Retry{MaxAttempts: 2}},
+			},
+			err: true,
+		},
+		{
+			name: "All required fields are provided",
+			poll: workflow.Poll{
+				Type:     "poll",
+				StepName: "name",
+				Until:    "response.done",
+				Timeout:  1000,
+				Request:  &workflow.Request{Url: "url", Method: "GET"},
+			},
+			err: false,
+		},
+	}
+
+	for _, tt := range tests {
+		err := tt.poll.Validate()
+
+		if tt.poll.Request != nil && tt.poll.Request.StepName != "" {
+			t.Errorf("in test %q; expected the request not to be named but got '%s'", tt.name, tt.poll.Request.StepName)
+		}
+
+		if !tt.err && err != nil {
+			t.Errorf("in test %q; expected no error but got %q", tt.name, err)
+			continue
+		}
+
+		if tt.err {
+			if err == nil {
+				t.Errorf("in test %q; expected error but got none", tt.name)
+				continue
+			}
+
+			var vErr *workflow.ValidationError
+			if !errors.As(err, &vErr) {
+				t.Errorf("in test %q; got %q; want validation error", tt.name, err)
+			}
+		}
+	}
+}
+
+func TestPoll_Execute(t *testing.T) {
+	pending := &http.Response{StatusCode: 200, Status: "200 OK", Body: []byte(`{"state":"pending"}`)}
+	done := &http.Response{StatusCode: 200, Status: "200 OK", Body: []byte(`{"state":"done","result":"42"}`)}
+
+	tests := []struct {
+		name        string
+		maxAttempts uint
+		responses   []*http.Response
+		calls       int
+		err         bool
+	}{
+		{
+			name:        "stops once condition is met",
+			maxAttempts: 5,
+			responses:   []*http.Response{pending, pending, done},
+			calls:       3,
+		},
+		{
+			name:        "fails once attempts are exhausted",
+			maxAttempts: 2,
+			responses:   []*http.Response{pending, pending, done},
+			calls:       2,
+			err:         true,
+		},
+	}
+
+	for _, tt := range tests {
+		poll := workflow.Poll{
+			Type:        "poll",
+			StepName:    "Job status",
+			Until:       "response.state == 'done'",
+			MaxAttempts: tt.maxAttempts,
+			Request: &workflow.Request{
+				StepName: "Job status",
+				Url:      "jobs",
+				Method:   "GET",
+				Store:    map[string]string{"result": "response.result"},
+			},
+		}
+
+		client := &sequenceHttpClient{responses: tt.responses}
+		mockStore := store.NewStore(nil)
+
+		logBuf := bytes.NewBuffer(nil)
+		logger := log.New(logBuf, logBuf, logBuf)
+
+		execContext, err := workflow.NewExecutionContext(workflow.WithGlobalOptions("https://domain.com", nil),
+			workflow.WithHttpClient(client),
+			workflow.WithStore(mockStore),
+			workflow.WithLogger(logger))
+		if err != nil {
+			t.Fatalf("failed to setup test: unable to create execution context")
+		}
+
+		err = poll.Execute(execContext)
+
+		if tt.err && err == nil {
+			t.Errorf("in test %q; expected error but got none", tt.name)
+		}
+
+		if !tt.err && err != nil {
+			t.Errorf("in test %q; expected no error but got %q\nLogs: %s", tt.name, err, logBuf.String())
+		}
+
+		if client.calls != tt.calls {
+			t.Errorf("in test %q; expected %d attempts but got %d", tt.name, tt.calls, client.calls)
+		}
+
+		if result, _ := mockStore.Get("result"); !tt.err && result != "42" {
+			t.Errorf("in test %q; expected 'result' to be stored from the final response but got '%v'", tt.name, result)
+		}
+	}
+}
+
+func TestPoll_ExecuteTimeout(t *testing.T) {
+	var calls atomic.Int32
+
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		// The second request does not respond before the timeout of the poll.
+		if calls.Add(1) > 1 {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"state":"pending"}`))
+	}))
+	defer server.Close()
+
+	poll := workflow.Poll{
+		Type:     "poll",
+		StepName: "Job status",
+		Until:    "response.state == 'done'",
+		Interval: 50,
+		Timeout:  300,
+		Request: &workflow.Request{
+			StepName: "Job status",
+			Url:      server.URL,
+			Method:   "GET",
+		},
+	}
+
+	logBuf := bytes.NewBuffer(nil)
+
+	execContext, err := workflow.NewExecutionContext(
+		workflow.WithHttpClient(http.NewClient(0)),
+		workflow.WithLogger(log.New(logBuf, logBuf, logBuf)))
+	if err != nil {
+		t.Fatalf("failed to setup test: unable to create execution context")
+	}
+
+	start := time.Now()
+	err = poll.Execute(execContext)
+	elapsed := time.Since(start)
+
+	if err == nil || !strings.Contains(err.Error(), "timed out after 300ms") {
+		t.Errorf("expected a timeout error but got %v", err)
+	}
+
+	if elapsed < 300*time.Millisecond || elapsed > 2*time.Second {
+		t.Errorf("expected the request in flight to be aborted at the timeout but the poll took %s", elapsed)
+	}
+}
@@ -0,0 +1,267 @@
+package workflow
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+	"github.com/santhanuv/srotas/internal/prompt"
+)
+
+// Kinds of values read by a [Prompt] step.
+const (
+	TextPrompt    = "text"    // Reads a line of text.
+	SecretPrompt  = "secret"  // Reads a line of text without echoing it.
+	ConfirmPrompt = "confirm" // Reads a yes or no answer as a boolean.
+	SelectPrompt  = "select"  // Reads a choice from a list of options.
+)
+
+// Prompt represents a step that asks the user for a value and stores it in the variable named As.
+// When no answer is given, the value of Default is used if provided.
+type Prompt struct {
+	Type       string      // The type of the step.
+	StepName   string      `yaml:"name"` // Identifier for the step.
+	Message    string      // Message displayed to the user. Defaults to the name of the step.
+	Kind       string      // Kind of the value to read. Defaults to text.
+	As         string      // The variable name to store the value.
+	Options    string      // Expression that evaluates to the list of options of a select prompt.
+	Default    string      // Expression that evaluates to the value used when no answer is given.
+	Validation string      `yaml:"validate"` // Expression that determines whether the value is valid.
+	cCheck     *vm.Program // Precompiled validation expression.
+}
+
+// Validate checks the fields of the [Prompt] step and returns a list of validation errors, if any.
+func (p *Prompt) Validate() error {
+	vErr := ValidationError{}
+
+	if p.StepName == "" {
+		vErr.Add(RequiredFieldError{Field: "name"})
+	}
+
+	if p.As == "" {
+		vErr.Add(RequiredFieldError{Field: "as"})
+	}
+
+	kinds := []string{TextPrompt, SecretPrompt, ConfirmPrompt, SelectPrompt}
+	if !slices.Contains(kinds, p.kind()) {
+		vErr.Add(fmt.Errorf("kind should be one of %s", strings.Join(kinds, ", ")))
+	}
+
+	if p.kind() == SelectPrompt && p.Options == "" {
+		vErr.Add(RequiredFieldError{Field: "options"})
+	}
+
+	if p.kind() != SelectPrompt && p.Options != "" {
+		vErr.Add(fmt.Errorf("options is only supported by select prompts"))
+	}
+
+	if vErr.HasError() {
+		return fmt.Errorf("prompt step: %w", &vErr)
+	}
+
+	return nil
+}
+
+func (p *Prompt) Name() string {
+	return p.StepName
+}
+
+// kind returns the kind of the value to read, which is text unless Kind is provided.
+func (p *Prompt) kind() string {
+	if p.Kind == "" {
+		return TextPrompt
+	}
+
+	return p.Kind
+}
+
+// Execute executes the step with the specified context.
+// The answer is validated before it is stored, with the value available as the value variable in the validation expression.
+func (p *Prompt) Execute(context *ExecutionContext) error {
+	variables := context.variables()
+
+	var defValue any
+
+	hasDefault := p.Default != ""
+	if hasDefault {
+		val, err := expr.Eval(p.Default, variables)
+		if err != nil {
+			return fmt.Errorf("prompt step '%s': invalid default expression '%s': %v", p.StepName, p.Default, err)
+		}
+
+		if _, ok := val.(bool); p.kind() == ConfirmPrompt && !ok {
+			return fmt.Errorf("prompt step '%s': default should be a boolean for confirm prompts", p.StepName)
+		}
+
+		defValue = val
+	}
+
+	var options []any
+
+	if p.kind() == SelectPrompt {
+		val, err := expr.Eval(p.Options, variables)
+		if err != nil {
+			return fmt.Errorf("prompt step '%s': invalid options expression '%s': %v", p.StepName, p.Options, err)
+		}
+
+		rv := reflect.ValueOf(val)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return fmt.Errorf("prompt step '%s': options should be a list, got %T", p.StepName, val)
+		}
+
+		if rv.Len() == 0 {
+			return fmt.Errorf("prompt step '%s': options should not be empty", p.StepName)
+		}
+
+		for i := range rv.Len() {
+			options = append(options, rv.Index(i).Interface())
+		}
+	}
+
+	var value any
+
+	question := prompt.Question{
+		Key:     p.StepName,
+		Message: p.message(defValue, hasDefault, options),
+		Secret:  p.kind() == SecretPrompt,
+		Validate: func(answer string) error {
+			val, err := p.convert(answer, defValue, hasDefault, options)
+			if err != nil {
+				return err
+			}
+
+			if err := p.check(val, variables); err != nil {
+				return err
+			}
+
+			value = val
+
+			return nil
+		},
+	}
+
+	if _, err := context.prompter.Ask(context.ctx, question); err != nil {
+		if reason := context.Interrupted(); reason != nil {
+			return fmt.Errorf("prompt step '%s' aborted: %w", p.StepName, reason)
+		}
+
+		return fmt.Errorf("prompt step '%s': %v", p.StepName, err)
+	}
+
+	context.store.Set(p.As, value)
+
+	context.logger.Debug("successfully executed prompt step '%s'", p.StepName)
+
+	return nil
+}
+
+// message returns the message displayed to the user, including the options and the default value.
+func (p *Prompt) message(defValue any, hasDefault bool, options []any) string {
+	msg := p.Message
+	if msg == "" {
+		msg = p.StepName
+	}
+
+	var b strings.Builder
+
+	b.WriteString(msg)
+
+	switch p.kind() {
+	case ConfirmPrompt:
+		switch {
+		case !hasDefault:
+			b.WriteString(" [y/n]")
+		case defValue.(bool):
+			b.WriteString(" [Y/n]")
+		default:
+			b.WriteString(" [y/N]")
+		}
+	case SelectPrompt:
+		for idx, option := range options {
+			fmt.Fprintf(&b, "\n  %d) %v", idx+1, option)
+		}
+
+		b.WriteString("\nChoose an option")
+
+		if hasDefault {
+			fmt.Fprintf(&b, " [%v]", defValue)
+		}
+	case TextPrompt:
+		if hasDefault {
+			fmt.Fprintf(&b, " [%v]", defValue)
+		}
+	}
+
+	b.WriteString(":")
+
+	return b.String()
+}
+
+// convert converts the answer to a value of the kind of the prompt.
+// An empty answer results in the default value if provided.
+func (p *Prompt) convert(answer string, defValue any, hasDefault bool, options []any) (any, error) {
+	if p.kind() != SecretPrompt {
+		answer = strings.TrimSpace(answer)
+	}
+
+	if answer == "" && hasDefault {
+		return defValue, nil
+	}
+
+	switch p.kind() {
+	case ConfirmPrompt:
+		switch strings.ToLower(answer) {
+		case "y", "yes", "true":
+			return true, nil
+		case "n", "no", "false":
+			return false, nil
+		}
+
+		return nil, fmt.Errorf("answer should be yes or no")
+	case SelectPrompt:
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+			return options[n-1], nil
+		}
+
+		for _, option := range options {
+			if fmt.Sprint(option) == answer {
+				return option, nil
+			}
+		}
+
+		return nil, fmt.Errorf("answer should be one of the options or its number")
+	}
+
+	return answer, nil
+}
+
+// check evaluates the validation expression with the value available as the value variable.
+func (p *Prompt) check(value any, variables map[string]any) error {
+	if p.Validation == "" {
+		return nil
+	}
+
+	env := maps.Clone(variables)
+	env["value"] = value
+
+	program, err := compileOnce(&p.cCheck, p.Validation, env, expr.AsBool())
+	if err != nil {
+		return fmt.Errorf("invalid validation expression '%s': %v", p.Validation, err)
+	}
+
+	output, err := expr.Run(program, env)
+	if err != nil {
+		return fmt.Errorf("invalid validation expression '%s': %v", p.Validation, err)
+	}
+
+	if !output.(bool) {
+		return fmt.Errorf("value does not satisfy '%s'", p.Validation)
+	}
+
+	return nil
+}
@@ -0,0 +1,269 @@
+package workflow
+
+import (
+	"bytes"
+	ctx "context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/santhanuv/srotas/internal/log"
+	"github.com/santhanuv/srotas/internal/prompt"
+	"github.com/santhanuv/srotas/internal/store"
+	"gopkg.in/yaml.v3"
+)
+
+func TestPrompt_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		prompt Prompt
+		err    bool
+	}{
+		{
+			name: "'name' is not provided",
+			prompt: Prompt{
+				Type: "prompt",
+				As:   "otp",
+			},
+			err: true,
+		},
+		{
+			name: "'as' is not provided",
+			prompt: Prompt{
+				Type:     "prompt",
+				StepName: "name",
+			},
+			err: true,
+		},
+		{
+			name: "invalid kind",
+			prompt: Prompt{
+				Type:     "prompt",
+				StepName: "name",
+				As:       "otp",
+				Kind:     "number",
+			},
+			err: true,
+		},
+		{
+			name: "'options' is not provided for select prompt",
+			prompt: Prompt{
+				Type:     "prompt",
+				StepName: "name",
+				As:       "account",
+				Kind:     SelectPrompt,
+			},
+			err: true,
+		},
+		{
+			name: "'options' is provided for text prompt",
+			prompt: Prompt{
+				Type:     "prompt",
+				StepName: "name",
+				As:       "account",
+				Options:  "accounts",
+			},
+			err: true,
+		},
+		{
+			name: "All required fields are provided",
+			prompt: Prompt{
+				Type:     "prompt",
+				StepName: "name",
+				As:       "otp",
+			},
+			err: false,
+		},
+	}
+
+	for _, tt := range tests {
+		prompt := tt.prompt
+		err := tt.prompt.Validate()
+
+		if !reflect.DeepEqual(tt.prompt, prompt) {
+			t.Errorf("in test %q; expected the step not to be modified but got %+v", tt.name, tt.prompt)
+		}
+
+		if !tt.err && err != nil {
+			t.Errorf("in test %q; expected no error but got %q", tt.name, err)
+			continue
+		}
+
+		if tt.err {
+			if err == nil {
+				t.Errorf("in test %q; expected error but got none", tt.name)
+				continue
+			}
+
+			var target *ValidationError
+			if !errors.As(err, &target) {
+				t.Errorf("in test %q; got %q; want validation error", tt.name, err)
+			}
+		}
+	}
+}
+
+func TestPrompt_Execute(t *testing.T) {
+	tests := []struct {
+		name    string
+		step    string
+		answers map[string][]string
+		want    any
+		err     string
+	}{
+		{
+			name:    "text answer",
+			step:    "{name: otp, as: value}",
+			answers: map[string][]string{"otp": {" 123456 "}},
+			want:    "123456",
+		},
+		{
+			name: "default is used when there is no answer",
+			step: "{name: env, as: value, default: 'defaultEnv'}",
+			want: "staging",
+		},
+		{
+			name:    "confirm answer",
+			step:    "{name: proceed, as: value, kind: confirm}",
+			answers: map[string][]string{"proceed": {"Yes"}},
+			want:    true,
+		},
+		{
+			name:    "invalid confirm answer",
+			step:    "{name: proceed, as: value, kind: confirm}",
+			answers: map[string][]string{"proceed": {"maybe"}},
+			err:     "answer should be yes or no",
+		},
+		{
+			name:    "select answer by number",
+			step:    "{name: account, as: value, kind: select, options: accounts}",
+			answers: map[string][]string{"account": {"2"}},
+			want:    map[string]any{"id": 2},
+		},
+		{
+			name:    "select answer by value",
+			step:    "{name: account, as: value, kind: select, options: 'map(accounts, .id)'}",
+			answers: map[string][]string{"account": {"1"}},
+			want:    1,
+		},
+		{
+			name:    "answer fails validation",
+			step:    "{name: otp, as: value, validate: 'len(value) == 6'}",
+			answers: map[string][]string{"otp": {"123"}},
+			err:     "value does not satisfy 'len(value) == 6'",
+		},
+	}
+
+	for _, tt := range tests {
+		var step Prompt
+		if err := yaml.Unmarshal([]byte(tt.step), &step); err != nil {
+			t.Fatalf("in test %q; failed to setup test: %v", tt.name, err)
+		}
+
+		if err := step.Validate(); err != nil {
+			t.Fatalf("in test %q; failed to setup test: %v", tt.name, err)
+		}
+
+		s := store.NewStore(map[string]any{
+			"defaultEnv": "staging",
+			"accounts":   []any{map[string]any{"id": 1}, map[string]any{"id": 2}},
+		})
+		logBuf := bytes.NewBuffer(nil)
+
+		context, err := NewExecutionContext(
+			WithStore(s),
+			WithLogger(log.New(logBuf, logBuf, logBuf)),
+			WithPrompter(prompt.NewAnswers(tt.answers)),
+		)
+		if err != nil {
+			t.Fatalf("failed to setup test: unable to create execution context")
+		}
+
+		err = step.Execute(context)
+
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("in test %q; expected error containing %q but got %v", tt.name, tt.err, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("in test %q; expected no error but got %q", tt.name, err)
+			continue
+		}
+
+		if got, _ := s.Get("value"); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("in test %q; got %#v; want %#v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// linePrompter answers questions with the lines sent on its channel, like a terminal.
+// A question abandoned when its context is done leaves the next line to the following question.
+type linePrompter struct {
+	lines chan string
+}
+
+func (l *linePrompter) Ask(c ctx.Context, q prompt.Question) (string, error) {
+	select {
+	case line := <-l.lines:
+		if q.Validate != nil {
+			if err := q.Validate(line); err != nil {
+				return "", err
+			}
+		}
+
+		return line, nil
+	case <-c.Done():
+		return "", ctx.Cause(c)
+	}
+}
+
+func TestPrompt_ExecuteInterrupted(t *testing.T) {
+	prompter := &linePrompter{lines: make(chan string)}
+	s := store.NewStore(nil)
+	logBuf := bytes.NewBuffer(nil)
+
+	context, err := NewExecutionContext(
+		WithStore(s),
+		WithLogger(log.New(logBuf, logBuf, logBuf)),
+		WithPrompter(prompter),
+	)
+	if err != nil {
+		t.Fatalf("failed to setup test: unable to create execution context")
+	}
+
+	def := &Definition{
+		Steps:    StepList{&Prompt{Type: "prompt", StepName: "otp", As: "otp", Kind: TextPrompt}},
+		Teardown: StepList{&Prompt{Type: "prompt", StepName: "cleanup", As: "cleanup", Kind: TextPrompt}},
+	}
+
+	time.AfterFunc(50*time.Millisecond, func() {
+		context.Interrupt(nil)
+		prompter.lines <- "yes"
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- Execute(def, context) }()
+
+	select {
+	case err = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the interrupted prompt to return")
+	}
+
+	if !errors.Is(err, ErrInterrupted) {
+		t.Errorf("expected error to be %v but got %v", ErrInterrupted, err)
+	}
+
+	if _, ok := s.Get("otp"); ok {
+		t.Errorf("expected 'otp' not to be set by the interrupted prompt")
+	}
+
+	if got, _ := s.Get("cleanup"); got != "yes" {
+		t.Errorf("expected the teardown prompt to receive the answer but got %v", got)
+	}
+}
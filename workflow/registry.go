@@ -0,0 +1,62 @@
+package workflow
+
+import (
+	"fmt"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// StepConstructor returns a new step of a registered type.
+// The returned step is decoded from the YAML node of the step and validated before it is executed.
+type StepConstructor func() Step
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]StepConstructor{}
+)
+
+// RegisterStep makes a custom step type available to configurations under the name stepType.
+// Configurations are parsed with the directory of the configuration as the working directory,
+// so relative paths can be resolved in the Validate method of the step.
+// It is intended to be called from the init function or main function of a custom binary,
+// and panics if constructor is nil or if stepType is already registered or is a built-in step type.
+func RegisterStep(stepType string, constructor StepConstructor) {
+	if constructor == nil {
+		panic(fmt.Sprintf("workflow: constructor of step type '%s' is nil", stepType))
+	}
+
+	if _, ok := newStepParser()[stepType]; ok {
+		panic(fmt.Sprintf("workflow: step type '%s' is a built-in step type", stepType))
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := registry[stepType]; ok {
+		panic(fmt.Sprintf("workflow: step type '%s' is already registered", stepType))
+	}
+
+	registry[stepType] = constructor
+}
+
+// registeredStepParser returns the parsing function of the step type registered with [RegisterStep], if any.
+func registeredStepParser(stepType string) (stepParserFunc, bool) {
+	registryMu.RLock()
+	constructor, ok := registry[stepType]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	return func(node *yaml.Node) (Step, error) {
+		step := constructor()
+
+		if err := parseStep(step, node); err != nil {
+			return nil, err
+		}
+
+		return step, nil
+	}, true
+}
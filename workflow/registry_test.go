@@ -0,0 +1,104 @@
+package workflow
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/santhanuv/srotas/internal/log"
+)
+
+// greetStep is a custom step registered by the tests.
+type greetStep struct {
+	StepName string `yaml:"name"`
+	Who      string
+	As       string
+}
+
+func (g *greetStep) Execute(context *ExecutionContext) error {
+	context.SetVariable(g.As, fmt.Sprintf("hello %s", g.Who))
+	return nil
+}
+
+func (g *greetStep) Validate() error {
+	if g.Who == "" {
+		vErr := ValidationError{}
+		vErr.Add(RequiredFieldError{Field: "who"})
+
+		return fmt.Errorf("greet step: %w", &vErr)
+	}
+
+	return nil
+}
+
+func (g *greetStep) Name() string { return g.StepName }
+
+func TestRegisterStep(t *testing.T) {
+	RegisterStep("test_greet", func() Step { return &greetStep{} })
+	t.Cleanup(func() { unregisterStep("test_greet") })
+
+	dir := t.TempDir()
+	path := writeConfig(t, dir, "greet.yaml", `
+steps:
+  - type: test_greet
+    step:
+      name: greet
+      who: world
+      as: greeting
+      when: "true"
+`)
+
+	logBuf := bytes.NewBuffer(nil)
+	logger := log.New(logBuf, logBuf, logBuf)
+
+	def, err := ParseConfig(path, logger)
+	if err != nil {
+		t.Fatalf("expected registered step to be parsed but got %v", err)
+	}
+
+	context, err := NewExecutionContext(WithLogger(logger))
+	if err != nil {
+		t.Fatalf("failed to setup test: unable to create execution context")
+	}
+
+	if err := Execute(def, context); err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	if got := context.Variables()["greeting"]; got != "hello world" {
+		t.Errorf("expected 'greeting' to be %q but got %v", "hello world", got)
+	}
+
+	invalid := writeConfig(t, dir, "invalid.yaml", `
+steps:
+  - type: test_greet
+    step:
+      name: greet
+`)
+
+	if _, err := ParseConfig(invalid, logger); err == nil {
+		t.Errorf("expected registered step to be validated but got no error")
+	}
+
+	tests := []struct {
+		name     string
+		stepType string
+		ctor     StepConstructor
+	}{
+		{name: "already registered type", stepType: "test_greet", ctor: func() Step { return &greetStep{} }},
+		{name: "built-in type", stepType: "http", ctor: func() Step { return &greetStep{} }},
+		{name: "nil constructor", stepType: "test_nil"},
+	}
+
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("in test %q; expected RegisterStep to panic", tt.name)
+				}
+			}()
+
+			RegisterStep(tt.stepType, tt.ctor)
+		}()
+	}
+}
@@ -0,0 +1,190 @@
+package workflow
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/expr-lang/expr"
+)
+
+// Set represents a step that evaluates expressions and stores the results as variables without sending a request.
+// A variable name may be a path (e.g. "user.address.city" or "items.-1") to assign a value within an existing map or list.
+type Set struct {
+	Type      string            // The type of the step.
+	StepName  string            `yaml:"name"` // Identifier for the step.
+	Variables map[string]string // Variable names or paths mapped to expressions.
+}
+
+// Validate checks the fields of the [Set] step and returns a list of validation errors, if any.
+func (s *Set) Validate() error {
+	vErr := ValidationError{}
+
+	if s.StepName == "" {
+		vErr.Add(RequiredFieldError{Field: "name"})
+	}
+
+	if len(s.Variables) == 0 {
+		vErr.Add(RequiredFieldError{Field: "variables"})
+	}
+
+	for name := range s.Variables {
+		if strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".") {
+			vErr.Add(fmt.Errorf("invalid variable path '%s'", name))
+		}
+	}
+
+	if vErr.HasError() {
+		return fmt.Errorf("set step: %w", &vErr)
+	}
+
+	return nil
+}
+
+func (s *Set) Name() string {
+	return s.StepName
+}
+
+// Execute executes the step with the specified context.
+// All expressions are evaluated before any variable is updated.
+func (s *Set) Execute(context *ExecutionContext) error {
+	vars := context.variables()
+	values := make(map[string]any, len(s.Variables))
+
+	for name, ve := range s.Variables {
+		val, err := expr.Eval(ve, vars)
+		if err != nil {
+			return fmt.Errorf("set step '%s': invalid expression '%s' for variable '%s': %v", s.StepName, ve, name, err)
+		}
+
+		values[name] = val
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		if err := setPath(context, name, values[name]); err != nil {
+			return fmt.Errorf("set step '%s': %v", s.StepName, err)
+		}
+	}
+
+	context.logger.Debug("successfully executed set step '%s'", s.StepName)
+
+	return nil
+}
+
+// setPath stores val in the variable identified by path.
+// If path contains a '.', the first segment identifies an existing map or list variable and the rest is
+// the path within it where the value is assigned. Only the maps and lists along the path are copied,
+// so the other values of the variable keep their types.
+func setPath(context *ExecutionContext, path string, val any) error {
+	root, subPath, nested := strings.Cut(path, ".")
+	if !nested {
+		context.store.Set(root, val)
+		return nil
+	}
+
+	current, ok := context.store.Get(root)
+	if !ok {
+		return fmt.Errorf("variable '%s' not found for path '%s'", root, path)
+	}
+
+	switch current.(type) {
+	case map[string]any, []any:
+	default:
+		return fmt.Errorf("variable '%s' should be a map or a list to set path '%s'", root, path)
+	}
+
+	updated, err := setIn(current, splitPath(subPath), val)
+	if err != nil {
+		return fmt.Errorf("unable to set path '%s': %v", path, err)
+	}
+
+	context.store.Set(root, updated)
+
+	return nil
+}
+
+// setIn returns a copy of current with val assigned at the path identified by keys.
+// Missing values along the path are created as lists if the key is an index and as maps otherwise.
+// A key of -1 appends to a list, and an index beyond the end of a list pads it with nil values.
+func setIn(current any, keys []string, val any) (any, error) {
+	if len(keys) == 0 {
+		return val, nil
+	}
+
+	key, rest := keys[0], keys[1:]
+
+	switch c := current.(type) {
+	case map[string]any:
+		updated := maps.Clone(c)
+		if updated == nil {
+			updated = map[string]any{}
+		}
+
+		item, err := setIn(c[key], rest, val)
+		if err != nil {
+			return nil, err
+		}
+
+		updated[key] = item
+
+		return updated, nil
+	case []any:
+		idx := len(c)
+		if key != "-1" {
+			var err error
+			if idx, err = strconv.Atoi(key); err != nil || idx < 0 {
+				return nil, fmt.Errorf("cannot set list item for non-numeric key '%s'", key)
+			}
+		}
+
+		updated := slices.Clone(c)
+		if idx >= len(updated) {
+			updated = append(updated, make([]any, idx-len(updated)+1)...)
+		}
+
+		item, err := setIn(updated[idx], rest, val)
+		if err != nil {
+			return nil, err
+		}
+
+		updated[idx] = item
+
+		return updated, nil
+	}
+
+	if _, err := strconv.Atoi(key); err == nil {
+		return setIn([]any{}, keys, val)
+	}
+
+	return setIn(map[string]any{}, keys, val)
+}
+
+// splitPath splits path into its keys, separated by '.'. A '.' preceded by a '\' is part of the key.
+func splitPath(path string) []string {
+	var keys []string
+	var key strings.Builder
+
+	for i := 0; i < len(path); i++ {
+		switch {
+		case path[i] == '\\' && i+1 < len(path):
+			i++
+			key.WriteByte(path[i])
+		case path[i] == '.':
+			keys = append(keys, key.String())
+			key.Reset()
+		default:
+			key.WriteByte(path[i])
+		}
+	}
+
+	return append(keys, key.String())
+}
@@ -0,0 +1,175 @@
+package workflow
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/santhanuv/srotas/internal/log"
+	"github.com/santhanuv/srotas/internal/store"
+)
+
+func TestSet_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		set  Set
+		err  bool
+	}{
+		{
+			name: "'name' is not provided",
+			set: Set{
+				Type:      "set",
+				Variables: map[string]string{"a": "1"},
+			},
+			err: true,
+		},
+		{
+			name: "'variables' field is not provided",
+			set: Set{
+				Type:     "set",
+				StepName: "name",
+			},
+			err: true,
+		},
+		{
+			name: "invalid variable path",
+			set: Set{
+				Type:      "set",
+				StepName:  "name",
+				Variables: map[string]string{"user.": "1"},
+			},
+			err: true,
+		},
+		{
+			name: "All required fields are provided",
+			set: Set{
+				Type:      "set",
+				StepName:  "name",
+				Variables: map[string]string{"a": "1"},
+			},
+			err: false,
+		},
+	}
+
+	for _, tt := range tests {
+		err := tt.set.Validate()
+
+		if !tt.err && err != nil {
+			t.Errorf("in test %q; expected no error but got %q", tt.name, err)
+			continue
+		}
+
+		if tt.err {
+			if err == nil {
+				t.Errorf("in test %q; expected error but got none", tt.name)
+				continue
+			}
+
+			var target *ValidationError
+			if !errors.As(err, &target) {
+				t.Errorf("in test %q; got %q; want validation error", tt.name, err)
+			}
+		}
+	}
+}
+
+func TestSet_Execute(t *testing.T) {
+	tests := []struct {
+		name      string
+		variables map[string]string
+		want      map[string]any
+		err       bool
+	}{
+		{
+			name:      "sets new variable",
+			variables: map[string]string{"ids": "map(users, .id)"},
+			want:      map[string]any{"ids": []any{1, 2}},
+		},
+		{
+			name:      "sets nested path in map",
+			variables: map[string]string{"user.address.city": "'Kochi'"},
+			want: map[string]any{"user": map[string]any{
+				"name":    "test",
+				"age":     30,
+				"address": map[string]any{"city": "Kochi"},
+			}},
+		},
+		{
+			name:      "sets item in list",
+			variables: map[string]string{"users.1.active": "true"},
+			want: map[string]any{"users": []any{
+				map[string]any{"id": 1},
+				map[string]any{"id": 2, "active": true},
+			}},
+		},
+		{
+			name:      "sets key containing escaped dot",
+			variables: map[string]string{`user.tags\.primary`: "'admin'"},
+			want: map[string]any{"user": map[string]any{
+				"name":         "test",
+				"age":          30,
+				"tags.primary": "admin",
+			}},
+		},
+		{
+			name:      "appends to list",
+			variables: map[string]string{"users.-1": "{'id': 3}"},
+			want: map[string]any{"users": []any{
+				map[string]any{"id": 1},
+				map[string]any{"id": 2},
+				map[string]any{"id": 3},
+			}},
+		},
+		{
+			name:      "nested path in undefined variable",
+			variables: map[string]string{"order.id": "1"},
+			err:       true,
+		},
+		{
+			name:      "nested path in scalar variable",
+			variables: map[string]string{"count.value": "1"},
+			err:       true,
+		},
+		{
+			name:      "non-numeric key in list",
+			variables: map[string]string{"users.first": "1"},
+			err:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		s := store.NewStore(map[string]any{
+			"count": 1,
+			"user":  map[string]any{"name": "test", "age": 30},
+			"users": []any{map[string]any{"id": 1}, map[string]any{"id": 2}},
+		})
+		logBuf := bytes.NewBuffer(nil)
+
+		context, err := NewExecutionContext(WithStore(s), WithLogger(log.New(logBuf, logBuf, logBuf)))
+		if err != nil {
+			t.Fatalf("failed to setup test: unable to create execution context")
+		}
+
+		step := Set{Type: "set", StepName: "set", Variables: tt.variables}
+		err = step.Execute(context)
+
+		if tt.err {
+			if err == nil {
+				t.Errorf("in test %q; expected error but got none", tt.name)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("in test %q; expected no error but got %q", tt.name, err)
+			continue
+		}
+
+		for name, want := range tt.want {
+			if got, _ := s.Get(name); !reflect.DeepEqual(got, want) {
+				t.Errorf("in test %q; expected variable '%s' to be '%v' but got '%v'", tt.name, name, want, got)
+			}
+		}
+	}
+}
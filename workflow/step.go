@@ -1,9 +1,13 @@
 package workflow
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
 	"gopkg.in/yaml.v3"
 )
 
@@ -18,6 +22,142 @@ type Step interface {
 	Name() string
 }
 
+// stepContainer is implemented by steps that contain nested steps.
+type stepContainer interface {
+	// stepLists returns the nested step lists of the step.
+	stepLists() []StepList
+}
+
+// walkSteps calls fn for each step in steps, including nested steps, in depth-first order.
+// Walking stops at the first error returned by fn.
+func walkSteps(steps StepList, fn func(step Step) error) error {
+	for _, step := range steps {
+		step = unwrapStep(step)
+
+		if err := fn(step); err != nil {
+			return err
+		}
+
+		container, ok := step.(stepContainer)
+		if !ok {
+			continue
+		}
+
+		for _, nested := range container.stepLists() {
+			if err := walkSteps(nested, fn); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// executeSteps executes the steps sequentially with the specified context, stopping at the first error.
+// The returned error is a [StepError] identifying the innermost failing step, unless it is a loop control signal.
+func executeSteps(steps StepList, context *ExecutionContext) error {
+	for _, step := range steps {
+		if reason := context.Interrupted(); reason != nil {
+			return fmt.Errorf("%w before step '%s'", reason, step.Name())
+		}
+
+		err := step.Execute(context)
+		if err == nil {
+			continue
+		}
+
+		var lc *loopControl
+		var se *StepError
+		if errors.As(err, &lc) || errors.As(err, &se) {
+			return err
+		}
+
+		return &StepError{Step: step.Name(), Err: err}
+	}
+
+	return nil
+}
+
+// guardedStep is a step that is executed only if its when expression evaluates to true.
+// It is applied while parsing to every step that defines the when field.
+type guardedStep struct {
+	Step
+	stepType string      // The type of the guarded step, used to identify it in errors.
+	when     string      // Expression that determines whether to execute the step.
+	cWhen    *vm.Program // Precompiled when expression.
+}
+
+// Execute executes the guarded step if the when expression evaluates to true, and skips it otherwise.
+func (g *guardedStep) Execute(context *ExecutionContext) error {
+	ok, err := evalWhen(g.when, &g.cWhen, context)
+	if err != nil {
+		return fmt.Errorf("%s step '%s': invalid expression '%s' for when: %v", g.stepType, g.Name(), g.when, err)
+	}
+
+	if !ok {
+		context.logger.Info("skipping step '%s': when condition '%s' is false", g.Name(), g.when)
+		return nil
+	}
+
+	return g.Step.Execute(context)
+}
+
+// unwrapStep returns the step guarded by a when expression, or step itself if it is not guarded.
+func unwrapStep(step Step) Step {
+	if g, ok := step.(*guardedStep); ok {
+		return g.Step
+	}
+
+	return step
+}
+
+// extractWhen removes the when field from the mapping node of a step and returns its value.
+func extractWhen(node *yaml.Node) (string, error) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return "", nil
+	}
+
+	for idx := 0; idx+1 < len(node.Content); idx += 2 {
+		if node.Content[idx].Value != "when" {
+			continue
+		}
+
+		value := node.Content[idx+1]
+		if value.Kind != yaml.ScalarNode {
+			return "", fmt.Errorf("line %d: when should be an expression", value.Line)
+		}
+
+		node.Content = append(node.Content[:idx], node.Content[idx+2:]...)
+
+		return value.Value, nil
+	}
+
+	return "", nil
+}
+
+// compileMu guards the programs compiled by compileOnce.
+var compileMu sync.Mutex
+
+// compileOnce returns *program, compiling the expression e with env as the environment into it on first use.
+// It is safe to call concurrently, as steps may be executed by multiple iterations of a loop at the same time.
+func compileOnce(program **vm.Program, e string, env map[string]any, options ...expr.Option) (*vm.Program, error) {
+	compileMu.Lock()
+	defer compileMu.Unlock()
+
+	if *program != nil {
+		return *program, nil
+	}
+
+	p, err := expr.Compile(e, append([]expr.Option{expr.Env(env)}, options...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	*program = p
+
+	return p, nil
+}
+
 // Represents a sequence of steps.
 type StepList []Step
 
@@ -0,0 +1,102 @@
+package workflow
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/santhanuv/srotas/internal/log"
+	"github.com/santhanuv/srotas/internal/store"
+)
+
+func TestStep_When(t *testing.T) {
+	dir := t.TempDir()
+
+	path := writeConfig(t, dir, "main.yaml", `
+steps:
+  - type: set
+    step:
+      name: skipped
+      when: "env == 'prod'"
+      variables:
+        skipped: "true"
+  - type: set
+    step:
+      name: executed
+      when: "env == 'dev'"
+      variables:
+        executed: "true"
+  - type: forEach
+    step:
+      name: loop
+      list: "[1, 2, 3]"
+      as: item
+      body:
+        - type: break
+          step:
+            name: stop
+            when: "item == 3"
+        - type: set
+          step:
+            name: collect
+            variables:
+              seen: "concat(seen, [item])"
+`)
+
+	logBuf := bytes.NewBuffer(nil)
+	logger := log.New(logBuf, logBuf, logBuf)
+
+	def, err := ParseConfig(path, logger)
+	if err != nil {
+		t.Fatalf("failed to setup test: %v", err)
+	}
+
+	s := store.NewStore(map[string]any{"env": "dev", "seen": []any{}})
+
+	context, err := NewExecutionContext(WithStore(s), WithLogger(logger))
+	if err != nil {
+		t.Fatalf("failed to setup test: unable to create execution context")
+	}
+
+	if err := Execute(def, context); err != nil {
+		t.Fatalf("expected no error but got %q", err)
+	}
+
+	if _, ok := s.Get("skipped"); ok {
+		t.Errorf("expected step 'skipped' to be skipped")
+	}
+
+	if _, ok := s.Get("executed"); !ok {
+		t.Errorf("expected step 'executed' to be executed")
+	}
+
+	want := []any{1, 2}
+	if seen, _ := s.Get("seen"); !reflect.DeepEqual(seen, want) {
+		t.Errorf("expected 'seen' to be %v but got %v", want, seen)
+	}
+
+	if !strings.Contains(logBuf.String(), "skipping step 'skipped'") {
+		t.Errorf("expected skipped step to be logged but got %q", logBuf.String())
+	}
+}
+
+func TestStep_WhenInvalidExpression(t *testing.T) {
+	step := &guardedStep{
+		Step:     &Set{Type: "set", StepName: "name", Variables: map[string]string{"a": "1"}},
+		stepType: "set",
+		when:     "missing +",
+	}
+
+	logBuf := bytes.NewBuffer(nil)
+
+	context, err := NewExecutionContext(WithLogger(log.New(logBuf, logBuf, logBuf)))
+	if err != nil {
+		t.Fatalf("failed to setup test: unable to create execution context")
+	}
+
+	err = step.Execute(context)
+	if err == nil || !strings.HasPrefix(err.Error(), "set step 'name': invalid expression 'missing +' for when") {
+		t.Errorf("expected invalid when expression error but got %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+package workflow
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+)
+
+// Switch represents a step that executes the steps of the first case matching the Value expression.
+// If no case matches, the Default steps are executed if provided.
+type Switch struct {
+	Type     string      // The type of the step.
+	StepName string      `yaml:"name"` // Identifier for the step.
+	Value    string      // Expression whose result is compared with each case.
+	cValue   *vm.Program // Precompiled value expression.
+	Cases    []Case      // Cases checked in order.
+	Default  StepList    // Steps to execute if no case matches.
+}
+
+// Case represents a branch of a [Switch] step.
+// A case matches either when Match is equal to the value of the switch, or when Condition evaluates to true.
+type Case struct {
+	Match      any         // Literal compared with the value of the switch.
+	Condition  string      // Expression that determines whether the case matches.
+	cCondition *vm.Program // Precompiled condition expression.
+	Steps      StepList    // Steps to execute if the case matches.
+}
+
+// Validate checks the fields of the [Switch] step and returns a list of validation errors, if any.
+func (s *Switch) Validate() error {
+	vErr := ValidationError{}
+
+	if s.StepName == "" {
+		vErr.Add(RequiredFieldError{Field: "name"})
+	}
+
+	if len(s.Cases) == 0 {
+		vErr.Add(RequiredFieldError{Field: "cases"})
+	}
+
+	for idx, c := range s.Cases {
+		if c.Match == nil && c.Condition == "" {
+			vErr.Add(fmt.Errorf("cases[%d]: either 'match' or 'condition' should be provided", idx))
+		}
+
+		if c.Match != nil && c.Condition != "" {
+			vErr.Add(fmt.Errorf("cases[%d]: only one of 'match' or 'condition' should be provided", idx))
+		}
+
+		if c.Match != nil && s.Value == "" {
+			vErr.Add(fmt.Errorf("cases[%d]: 'value' is required to use 'match'", idx))
+		}
+
+		if c.Steps == nil {
+			vErr.Add(RequiredFieldError{Field: fmt.Sprintf("cases[%d].steps", idx)})
+		}
+	}
+
+	if vErr.HasError() {
+		return fmt.Errorf("switch step: %w", &vErr)
+	}
+
+	return nil
+}
+
+func (s *Switch) Name() string {
+	return s.StepName
+}
+
+func (s *Switch) stepLists() []StepList {
+	lists := make([]StepList, 0, len(s.Cases)+1)
+	for _, c := range s.Cases {
+		lists = append(lists, c.Steps)
+	}
+
+	return append(lists, s.Default)
+}
+
+// Execute executes the step with the specified context.
+// The result of Value is available as the value variable while evaluating the case conditions.
+func (s *Switch) Execute(context *ExecutionContext) error {
+	variables := context.variables()
+
+	var value any
+
+	if s.Value != "" {
+		program, err := compileOnce(&s.cValue, s.Value, variables)
+
+		if err != nil {
+			return fmt.Errorf("switch step '%s': %v", s.StepName, err)
+		}
+
+		output, err := expr.Run(program, variables)
+
+		if err != nil {
+			return fmt.Errorf("switch step '%s': %v", s.StepName, err)
+		}
+
+		value = output
+		variables["value"] = value
+	}
+
+	executionSteps := s.Default
+	matched := "default"
+
+	for idx := range s.Cases {
+		c := &s.Cases[idx]
+
+		ok, err := c.matches(value, variables)
+		if err != nil {
+			return fmt.Errorf("switch step '%s': cases[%d]: %v", s.StepName, idx, err)
+		}
+
+		if ok {
+			executionSteps = c.Steps
+			matched = fmt.Sprintf("cases[%d]", idx)
+			break
+		}
+	}
+
+	context.logger.Debug("switch step '%s' matched %s", s.StepName, matched)
+
+	if err := executeSteps(executionSteps, context); err != nil {
+		return err
+	}
+
+	context.logger.Debug("successfully completed the execution of switch step '%s'.", s.StepName)
+
+	return nil
+}
+
+// matches reports whether the case matches the value of the switch.
+func (c *Case) matches(value any, variables map[string]any) (bool, error) {
+	if c.Condition == "" {
+		return equalValues(c.Match, value), nil
+	}
+
+	program, err := compileOnce(&c.cCondition, c.Condition, variables, expr.AsBool())
+
+	if err != nil {
+		return false, err
+	}
+
+	output, err := expr.Run(program, variables)
+
+	if err != nil {
+		return false, err
+	}
+
+	return output.(bool), nil
+}
+
+// equalValues reports whether a and b are equal, treating numbers of different types with the same value as equal.
+func equalValues(a, b any) bool {
+	af, aNum := toFloat(a)
+	bf, bNum := toFloat(b)
+
+	if aNum && bNum {
+		return af == bf
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// toFloat converts numeric values to float64, reporting whether v is a number.
+func toFloat(v any) (float64, bool) {
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+
+	return 0, false
+}
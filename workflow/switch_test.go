@@ -0,0 +1,140 @@
+package workflow
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/santhanuv/srotas/internal/log"
+	"github.com/santhanuv/srotas/internal/store"
+)
+
+func TestSwitch_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		Switch Switch
+		err    bool
+	}{
+		{
+			name: "'name' is not provided",
+			Switch: Switch{
+				Type:  "switch",
+				Value: "value",
+				Cases: []Case{{Match: 1, Steps: StepList{}}},
+			},
+			err: true,
+		},
+		{
+			name: "'cases' field is empty",
+			Switch: Switch{
+				Type:     "switch",
+				StepName: "name",
+				Value:    "value",
+				Cases:    []Case{},
+			},
+			err: true,
+		},
+		{
+			name: "case has neither match nor condition",
+			Switch: Switch{
+				Type:     "switch",
+				StepName: "name",
+				Value:    "value",
+				Cases:    []Case{{Steps: StepList{}}},
+			},
+			err: true,
+		},
+		{
+			name: "case has both match and condition",
+			Switch: Switch{
+				Type:     "switch",
+				StepName: "name",
+				Value:    "value",
+				Cases:    []Case{{Match: 1, Condition: "true", Steps: StepList{}}},
+			},
+			err: true,
+		},
+		{
+			name: "match is used without value",
+			Switch: Switch{
+				Type:     "switch",
+				StepName: "name",
+				Cases:    []Case{{Match: 1, Steps: StepList{}}},
+			},
+			err: true,
+		},
+		{
+			name: "All required fields are provided",
+			Switch: Switch{
+				Type:     "switch",
+				StepName: "name",
+				Value:    "value",
+				Cases:    []Case{{Match: 1, Steps: StepList{}}, {Condition: "value > 1", Steps: StepList{}}},
+			},
+			err: false,
+		},
+	}
+
+	for _, tt := range tests {
+		err := tt.Switch.Validate()
+
+		if !tt.err && err != nil {
+			t.Errorf("in test %q; expected no error but got %q", tt.name, err)
+			continue
+		}
+
+		if tt.err {
+			if err == nil {
+				t.Errorf("in test %q; expected error but got none", tt.name)
+				continue
+			}
+
+			var target *ValidationError
+			if !errors.As(err, &target) {
+				t.Errorf("in test %q; got %q; want validation error", tt.name, err)
+			}
+		}
+	}
+}
+
+func TestSwitch_Execute(t *testing.T) {
+	tests := []struct {
+		name   string
+		status any
+		want   string
+	}{
+		{name: "literal case matches", status: float64(404), want: "not found"},
+		{name: "condition case matches", status: 503, want: "server error"},
+		{name: "default when no case matches", status: 200, want: "default"},
+	}
+
+	for _, tt := range tests {
+		step := Switch{
+			Type:     "switch",
+			StepName: "status",
+			Value:    "status",
+			Cases: []Case{
+				{Match: 404, Steps: StepList{&storeStep{vars: map[string]any{"branch": "not found"}}}},
+				{Condition: "value >= 500", Steps: StepList{&storeStep{vars: map[string]any{"branch": "server error"}}}},
+			},
+			Default: StepList{&storeStep{vars: map[string]any{"branch": "default"}}},
+		}
+
+		s := store.NewStore(map[string]any{"status": tt.status})
+		logBuf := bytes.NewBuffer(nil)
+
+		context, err := NewExecutionContext(WithStore(s), WithLogger(log.New(logBuf, logBuf, logBuf)))
+		if err != nil {
+			t.Fatalf("failed to setup test: unable to create execution context")
+		}
+
+		if err := step.Execute(context); err != nil {
+			t.Errorf("in test %q; expected no error but got %q", tt.name, err)
+			continue
+		}
+
+		if branch, _ := s.Get("branch"); branch != tt.want {
+			t.Errorf("in test %q; expected branch %q to be executed but got '%v'", tt.name, tt.want, branch)
+		}
+	}
+}
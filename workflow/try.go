@@ -0,0 +1,133 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Try represents a step that handles errors of its Steps.
+// If a step fails, the Catch steps are executed with the error available as the error variable.
+// The Finally steps are always executed, whether the Steps fail or not.
+// The error of the failing step is returned unless Catch handles it.
+type Try struct {
+	Type     string   // The type of the step.
+	StepName string   `yaml:"name"` // Identifier for the step.
+	Steps    StepList // Steps to execute.
+	Catch    *Catch   // Steps to execute if any of Steps fail.
+	Finally  StepList // Steps that are always executed after Steps and Catch.
+}
+
+// Catch represents the error handling of a [Try] step.
+type Catch struct {
+	Steps   StepList // Steps to execute when an error occurs.
+	Handled bool     // Handles the error if true, so that it is not returned by the try step.
+}
+
+// Validate checks the fields of the [Try] step and returns a list of validation errors, if any.
+func (t *Try) Validate() error {
+	vErr := ValidationError{}
+
+	if t.StepName == "" {
+		vErr.Add(RequiredFieldError{Field: "name"})
+	}
+
+	if t.Steps == nil {
+		vErr.Add(RequiredFieldError{Field: "steps"})
+	}
+
+	if t.Catch == nil && t.Finally == nil {
+		vErr.Add(fmt.Errorf("either 'catch' or 'finally' should be provided"))
+	}
+
+	if t.Catch != nil && t.Catch.Steps == nil {
+		vErr.Add(RequiredFieldError{Field: "catch.steps"})
+	}
+
+	if vErr.HasError() {
+		return fmt.Errorf("try step: %w", &vErr)
+	}
+
+	return nil
+}
+
+func (t *Try) Name() string {
+	return t.StepName
+}
+
+func (t *Try) stepLists() []StepList {
+	lists := []StepList{t.Steps, t.Finally}
+	if t.Catch != nil {
+		lists = append(lists, t.Catch.Steps)
+	}
+
+	return lists
+}
+
+// Execute executes the step with the specified context.
+func (t *Try) Execute(context *ExecutionContext) error {
+	err := executeSteps(t.Steps, context)
+
+	var lc *loopControl
+	if err != nil && !errors.As(err, &lc) && !errors.Is(err, context.Interrupted()) && t.Catch != nil {
+		err = t.catch(context, err)
+	}
+
+	if fErr := executeSteps(t.Finally, context.uninterruptible()); fErr != nil {
+		if errors.As(fErr, &lc) {
+			return fErr
+		}
+
+		if err == nil || errors.As(err, &lc) {
+			return fmt.Errorf("finally of try step '%s' failed: %w", t.StepName, fErr)
+		}
+
+		return fmt.Errorf("%w\nfinally of try step '%s' also failed: %v", err, t.StepName, fErr)
+	}
+
+	context.logger.Debug("successfully completed the execution of try step '%s'.", t.StepName)
+
+	return err
+}
+
+// catch executes the catch steps for the given error and returns the error that remains after handling it.
+func (t *Try) catch(context *ExecutionContext, err error) error {
+	failedStep := t.StepName
+
+	var se *StepError
+	if errors.As(err, &se) {
+		failedStep = se.Step
+	}
+
+	context.logger.Info("try step '%s': step '%s' failed, executing catch", t.StepName, failedStep)
+
+	prev, hasPrev := context.store.Get("error")
+	context.store.Set("error", map[string]any{
+		"message":  err.Error(),
+		"step":     failedStep,
+		"response": context.lastResponse,
+	})
+
+	defer func() {
+		if hasPrev {
+			context.store.Set("error", prev)
+		} else {
+			context.store.Remove("error")
+		}
+	}()
+
+	if cErr := executeSteps(t.Catch.Steps, context); cErr != nil {
+		var lc *loopControl
+		if errors.As(cErr, &lc) {
+			return cErr
+		}
+
+		return fmt.Errorf("%w\ncatch of try step '%s' also failed: %v", err, t.StepName, cErr)
+	}
+
+	if t.Catch.Handled {
+		context.logger.Info("try step '%s': error of step '%s' is handled", t.StepName, failedStep)
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,175 @@
+package workflow
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/santhanuv/srotas/internal/log"
+	"github.com/santhanuv/srotas/internal/store"
+)
+
+func TestTry_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		try  Try
+		err  bool
+	}{
+		{
+			name: "'name' is not provided",
+			try: Try{
+				Type:    "try",
+				Steps:   StepList{},
+				Finally: StepList{},
+			},
+			err: true,
+		},
+		{
+			name: "'steps' field is not provided",
+			try: Try{
+				Type:     "try",
+				StepName: "name",
+				Finally:  StepList{},
+			},
+			err: true,
+		},
+		{
+			name: "neither catch nor finally is provided",
+			try: Try{
+				Type:     "try",
+				StepName: "name",
+				Steps:    StepList{},
+			},
+			err: true,
+		},
+		{
+			name: "catch steps are not provided",
+			try: Try{
+				Type:     "try",
+				StepName: "name",
+				Steps:    StepList{},
+				Catch:    &Catch{Handled: true},
+			},
+			err: true,
+		},
+		{
+			name: "All required fields are provided",
+			try: Try{
+				Type:     "try",
+				StepName: "name",
+				Steps:    StepList{},
+				Catch:    &Catch{Steps: StepList{}},
+			},
+			err: false,
+		},
+	}
+
+	for _, tt := range tests {
+		err := tt.try.Validate()
+
+		if !tt.err && err != nil {
+			t.Errorf("in test %q; expected no error but got %q", tt.name, err)
+			continue
+		}
+
+		if tt.err {
+			if err == nil {
+				t.Errorf("in test %q; expected error but got none", tt.name)
+				continue
+			}
+
+			var target *ValidationError
+			if !errors.As(err, &target) {
+				t.Errorf("in test %q; got %q; want validation error", tt.name, err)
+			}
+		}
+	}
+}
+
+// failStep is a test step that always fails with the given error.
+type failStep struct {
+	name string
+	err  error
+}
+
+func (f *failStep) Execute(context *ExecutionContext) error { return f.err }
+
+func (f *failStep) Validate() error { return nil }
+
+func (f *failStep) Name() string { return f.name }
+
+func TestTry_Execute(t *testing.T) {
+	stepErr := errors.New("order creation failed")
+
+	tests := []struct {
+		name    string
+		steps   StepList
+		handled bool
+		err     bool
+		caught  any
+	}{
+		{
+			name:    "error is handled by catch",
+			steps:   StepList{&failStep{name: "create order", err: stepErr}},
+			handled: true,
+			caught:  "create order",
+		},
+		{
+			name:   "error propagates when not handled",
+			steps:  StepList{&failStep{name: "create order", err: stepErr}},
+			err:    true,
+			caught: "create order",
+		},
+		{
+			name:  "catch is skipped without error",
+			steps: StepList{&storeStep{vars: map[string]any{"order": 1}}},
+		},
+	}
+
+	for _, tt := range tests {
+		try := Try{
+			Type:     "try",
+			StepName: "try",
+			Steps:    tt.steps,
+			Catch: &Catch{
+				Steps: StepList{&Set{
+					Type:      "set",
+					StepName:  "record error",
+					Variables: map[string]string{"caught": "error.step"},
+				}},
+				Handled: tt.handled,
+			},
+			Finally: StepList{&storeStep{vars: map[string]any{"cleaned": true}}},
+		}
+
+		s := store.NewStore(nil)
+		logBuf := bytes.NewBuffer(nil)
+
+		context, err := NewExecutionContext(WithStore(s), WithLogger(log.New(logBuf, logBuf, logBuf)))
+		if err != nil {
+			t.Fatalf("failed to setup test: unable to create execution context")
+		}
+
+		err = try.Execute(context)
+
+		if tt.err && !errors.Is(err, stepErr) {
+			t.Errorf("in test %q; expected error %q but got %v", tt.name, stepErr, err)
+		}
+
+		if !tt.err && err != nil {
+			t.Errorf("in test %q; expected no error but got %q", tt.name, err)
+		}
+
+		if caught, _ := s.Get("caught"); caught != tt.caught {
+			t.Errorf("in test %q; expected caught step to be '%v' but got '%v'", tt.name, tt.caught, caught)
+		}
+
+		if cleaned, _ := s.Get("cleaned"); cleaned != true {
+			t.Errorf("in test %q; expected finally to be executed", tt.name)
+		}
+
+		if _, ok := s.Get("error"); ok {
+			t.Errorf("in test %q; expected 'error' variable to be removed after catch", tt.name)
+		}
+	}
+}
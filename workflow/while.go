@@ -10,15 +10,18 @@ import (
 // While represents a loop that executes the steps in Body while the Condition evaluates to true.
 // Init defines the initial variables for the loop.
 // Update specifies variable expressions that are updated after each iteration.
+// The loop fails once it exceeds MaxIterations, or the default maximum of the execution if not provided, or MaxDuration.
 type While struct {
-	Type       string                 // The type of the step.
-	StepName   string                 `yaml:"name"` // Identifier for the step.
-	Init       map[string]any         // Initial variables for the loop.
-	Condition  string                 // Expr conditional expression for the loop.
-	Update     map[string]string      // Variable expressions to update after each iteration.
-	cCondition *vm.Program            // Compiled condition.
-	cUpdation  map[string]*vm.Program // Compiled update expressions.
-	Body       StepList               // Steps to execute in each iteration.
+	Type          string                 // The type of the step.
+	StepName      string                 `yaml:"name"` // Identifier for the step.
+	Init          map[string]any         // Initial variables for the loop.
+	Condition     string                 // Expr conditional expression for the loop.
+	Update        map[string]string      // Variable expressions to update after each iteration.
+	MaxIterations uint                   `yaml:"max_iterations"` // Maximum number of iterations.
+	MaxDuration   uint                   `yaml:"max_duration"`   // Maximum time (milliseconds) allowed for the loop.
+	cCondition    *vm.Program            // Compiled condition.
+	cUpdation     map[string]*vm.Program // Compiled update expressions.
+	Body          StepList               // Steps to execute in each iteration.
 }
 
 // Validate checks the fields of the [While] step and returns a list of validation errors, if any.
@@ -48,58 +51,55 @@ func (w *While) Name() string {
 	return w.StepName
 }
 
+func (w *While) stepLists() []StepList {
+	return []StepList{w.Body}
+}
+
 // Execute executes the step with the specified context.
 func (w *While) Execute(context *ExecutionContext) error {
-	variables := context.store.Map()
+	variables := context.variables()
 
 	for key, val := range w.Init {
 		if _, ok := variables[key]; ok {
 			return fmt.Errorf("while step '%s': variable '%s' already defined", w.StepName, key)
 		}
 
-		variables[key] = val
+		context.store.Set(key, val)
 	}
 
+	variables = context.variables()
+
 	defer func() {
 		for name := range w.Init {
 			context.store.Remove(name)
 		}
 	}()
 
-	if w.cCondition == nil {
-		if w.Condition == "" {
-			return fmt.Errorf("while step '%s': condition is mandatory", w.StepName)
-		}
-
-		program, err := expr.Compile(w.Condition, expr.Env(variables), expr.AsBool())
+	if w.Condition == "" {
+		return fmt.Errorf("while step '%s': condition is mandatory", w.StepName)
+	}
 
-		if err != nil {
-			return err
-		}
+	condition, err := compileOnce(&w.cCondition, w.Condition, variables, expr.AsBool())
 
-		w.cCondition = program
+	if err != nil {
+		return err
 	}
 
-	if w.cUpdation == nil {
-		w.cUpdation = make(map[string]*vm.Program, len(w.Update))
-
-		if w.Update == nil {
-			context.logger.Error("while step '%s': no loop updatation is set", w.StepName)
-		}
+	limit := newLoopLimit(context, w.StepName, w.MaxIterations, w.MaxDuration)
 
-		for key, uExpr := range w.Update {
-			program, err := expr.Compile(uExpr, expr.Env(variables))
+	if w.Update == nil {
+		context.logger.Error("while step '%s': no loop updatation is set, the loop fails after %d iterations", w.StepName, limit.maxIterations)
+	}
 
-			if err != nil {
-				return err
-			}
+	updation, err := w.compileUpdation(variables)
 
-			w.cUpdation[key] = program
-		}
+	if err != nil {
+		return err
 	}
 
 	for {
-		output, err := expr.Run(w.cCondition, variables)
+		variables = context.variables()
+		output, err := expr.Run(condition, variables)
 
 		if err != nil {
 			return err
@@ -111,24 +111,72 @@ func (w *While) Execute(context *ExecutionContext) error {
 			break
 		}
 
-		for _, step := range w.Body {
-			err := step.Execute(context)
+		if err := limit.next(w.loopVariables(variables)); err != nil {
+			return err
+		}
 
-			if err != nil {
-				return err
-			}
+		stop, err := executeLoopBody(w.Body, context)
+		if err != nil {
+			return err
 		}
 
-		for key, uExpr := range w.cUpdation {
+		if stop {
+			break
+		}
+
+		variables = context.variables()
+		for key, uExpr := range updation {
 			output, err := expr.Run(uExpr, variables)
 
 			if err != nil {
 				return err
 			}
 
-			variables[key] = output
+			context.store.Set(key, output)
 		}
 	}
 
 	return nil
 }
+
+// loopVariables returns the values of the variables initialized or updated by the loop.
+func (w *While) loopVariables(variables map[string]any) map[string]any {
+	loopVars := make(map[string]any, len(w.Init)+len(w.Update))
+
+	for name := range w.Init {
+		loopVars[name] = variables[name]
+	}
+
+	for name := range w.Update {
+		loopVars[name] = variables[name]
+	}
+
+	return loopVars
+}
+
+// compileUpdation returns the compiled update expressions, compiling them with variables as the environment on first use.
+// It is safe to call concurrently.
+func (w *While) compileUpdation(variables map[string]any) (map[string]*vm.Program, error) {
+	compileMu.Lock()
+	defer compileMu.Unlock()
+
+	if w.cUpdation != nil {
+		return w.cUpdation, nil
+	}
+
+	updation := make(map[string]*vm.Program, len(w.Update))
+
+	for key, uExpr := range w.Update {
+		program, err := expr.Compile(uExpr, expr.Env(variables))
+
+		if err != nil {
+			return nil, err
+		}
+
+		updation[key] = program
+	}
+
+	w.cUpdation = updation
+
+	return updation, nil
+}
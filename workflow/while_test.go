@@ -1,8 +1,12 @@
 package workflow
 
 import (
+	"bytes"
 	"errors"
 	"testing"
+	"time"
+
+	"github.com/santhanuv/srotas/internal/log"
 )
 
 func TestWhile_Validate(t *testing.T) {
@@ -71,3 +75,86 @@ func TestWhile_Validate(t *testing.T) {
 		}
 	}
 }
+
+func TestWhile_ExecuteLimits(t *testing.T) {
+	tests := []struct {
+		name          string
+		while         While
+		maxIterations uint
+		iterations    uint
+		variables     map[string]any
+		duration      bool
+	}{
+		{
+			name: "max_iterations of the step is exceeded",
+			while: While{
+				Init:          map[string]any{"page": 1},
+				Condition:     "page > 0",
+				Update:        map[string]string{"page": "page + 1"},
+				MaxIterations: 5,
+			},
+			iterations: 5,
+			variables:  map[string]any{"page": 6},
+		},
+		{
+			name: "default max_iterations of the execution is exceeded",
+			while: While{
+				Init:      map[string]any{"page": 1, "done": false},
+				Condition: "!done",
+				Update:    map[string]string{"page": "page + 1"},
+			},
+			maxIterations: 3,
+			iterations:    3,
+			variables:     map[string]any{"page": 4, "done": false},
+		},
+		{
+			name: "max_duration is exceeded",
+			while: While{
+				Condition:     "true",
+				MaxIterations: 1 << 30,
+				MaxDuration:   20,
+			},
+			variables: map[string]any{},
+			duration:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt.while.Type = "while"
+		tt.while.StepName = "loop"
+		tt.while.Body = StepList{}
+
+		logBuf := bytes.NewBuffer(nil)
+
+		context, err := NewExecutionContext(WithMaxIterations(tt.maxIterations), WithLogger(log.New(logBuf, logBuf, logBuf)))
+		if err != nil {
+			t.Fatalf("failed to setup test: unable to create execution context")
+		}
+
+		err = tt.while.Execute(context)
+
+		var limitErr *LoopLimitError
+		if !errors.As(err, &limitErr) {
+			t.Errorf("in test %q; expected loop limit error but got %v", tt.name, err)
+			continue
+		}
+
+		if tt.duration {
+			if limitErr.MaxDuration != 20*time.Millisecond || limitErr.Elapsed < limitErr.MaxDuration {
+				t.Errorf("in test %q; expected max_duration to be exceeded but got %q", tt.name, limitErr)
+			}
+		} else if limitErr.Iterations != tt.iterations {
+			t.Errorf("in test %q; expected %d iterations but got %d", tt.name, tt.iterations, limitErr.Iterations)
+		}
+
+		if len(limitErr.Variables) != len(tt.variables) {
+			t.Errorf("in test %q; expected loop variables %v but got %v", tt.name, tt.variables, limitErr.Variables)
+		}
+
+		for name, want := range tt.variables {
+			if got := limitErr.Variables[name]; got != want {
+				t.Errorf("in test %q; expected loop variable '%s' to be %v but got %v", tt.name, name, want, got)
+			}
+		}
+	}
+}